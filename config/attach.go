@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DefaultDetachKeys = "ctrl-p,ctrl-q"
+
+// AttachConfig sets the key sequence that detaches from an attached container, in docker's format ("ctrl-p,ctrl-q")
+type AttachConfig struct {
+	DetachKeys string `yaml:"detach_keys"`
+}
+
+func DetachKeys() string {
+	if keys := Get().Attach.DetachKeys; keys != "" {
+		return keys
+	}
+	return DefaultDetachKeys
+}
+
+// DetachKeySequence returns the bytes the terminal sends when the detach keys are typed
+func DetachKeySequence() []byte {
+	sequence, err := parseDetachKeys(DetachKeys())
+	if err != nil {
+		sequence, _ = parseDetachKeys(DefaultDetachKeys)
+	}
+	return sequence
+}
+
+// parseDetachKeys accepts single characters and "ctrl-<key>" with keys from 'a' to 'z' and @ [ \ ] ^ _
+func parseDetachKeys(keys string) ([]byte, error) {
+	sequence := make([]byte, 0)
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.TrimSpace(key)
+		switch {
+		case len(key) == 1:
+			sequence = append(sequence, key[0])
+		case strings.HasPrefix(key, "ctrl-") && len(key) == len("ctrl-")+1:
+			c := strings.ToLower(key)[len(key)-1]
+			switch {
+			case c >= 'a' && c <= 'z':
+				sequence = append(sequence, c-'a'+1)
+			case c == '@':
+				sequence = append(sequence, 0)
+			case c >= '[' && c <= '_':
+				sequence = append(sequence, c-'['+27)
+			default:
+				return nil, fmt.Errorf("bad detach key '%s'", key)
+			}
+		default:
+			return nil, fmt.Errorf("bad detach key '%s'", key)
+		}
+	}
+	return sequence, nil
+}
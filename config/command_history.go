@@ -0,0 +1,32 @@
+package config
+
+const MaxCommandHistory = 50
+
+var command_history_file = &stateFile{name: "command_history.yaml"}
+
+// CommandHistory returns the commands that were run, oldest first
+func CommandHistory() []string {
+	var history []string
+	if err := command_history_file.load(&history); err != nil {
+		return nil
+	}
+	return history
+}
+
+// AddToCommandHistory moves the command to the end of the history, dropping the oldest commands
+func AddToCommandHistory(command string) error {
+	var history []string
+	return command_history_file.update(&history, func() {
+		updated := make([]string, 0, len(history)+1)
+		for _, previous := range history {
+			if previous != command {
+				updated = append(updated, previous)
+			}
+		}
+		updated = append(updated, command)
+		if len(updated) > MaxCommandHistory {
+			updated = updated[len(updated)-MaxCommandHistory:]
+		}
+		history = updated
+	})
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+type Config struct {
+	StopTimeouts TimeoutsConfig   `yaml:"stop_timeouts"`
+	Debug        DebugConfig      `yaml:"debug"`
+	Exec         ExecConfig       `yaml:"exec"`
+	Attach       AttachConfig     `yaml:"attach"`
+	Recordings   RecordingsConfig `yaml:"recordings"`
+	Logs         LogsConfig       `yaml:"logs"`
+}
+
+var (
+	current      Config
+	current_lock sync.RWMutex
+)
+
+func DefaultPath() string {
+	config_dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(config_dir, "dc-top", "config.yaml")
+}
+
+// Init loads the config file at path, a missing file is only an error if `must_exist` is set
+func Init(path string, must_exist bool) error {
+	current_lock.Lock()
+	defer current_lock.Unlock()
+
+	current = Config{}
+	if path == "" {
+		return nil
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !must_exist {
+			log.Printf("No config file at %s, using defaults", path)
+			return nil
+		}
+		return err
+	}
+	if err = yaml.Unmarshal(contents, &current); err != nil {
+		return fmt.Errorf("failed to parse config file '%s': %s", path, err)
+	}
+	if current.Attach.DetachKeys != "" {
+		if _, err = parseDetachKeys(current.Attach.DetachKeys); err != nil {
+			return err
+		}
+	}
+	return current.StopTimeouts.validate()
+}
+
+func Get() Config {
+	current_lock.RLock()
+	defer current_lock.RUnlock()
+	return current
+}
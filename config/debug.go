@@ -0,0 +1,26 @@
+package config
+
+const (
+	DefaultDebugImage = "busybox:latest"
+	DefaultDebugShell = "sh"
+)
+
+// DebugConfig sets the toolbox image of debug sidecars and the shell that is started inside it
+type DebugConfig struct {
+	Image string `yaml:"image"`
+	Shell string `yaml:"shell"`
+}
+
+func DebugImage() string {
+	if image := Get().Debug.Image; image != "" {
+		return image
+	}
+	return DefaultDebugImage
+}
+
+func DebugShell() string {
+	if shell := Get().Debug.Shell; shell != "" {
+		return shell
+	}
+	return DefaultDebugShell
+}
@@ -0,0 +1,42 @@
+package config
+
+var DefaultShells = []string{"bash", "zsh", "ash", "sh"}
+
+// ExecConfig sets the shells that are looked for, in order, when opening a shell inside a container
+type ExecConfig struct {
+	Shells []string `yaml:"shells"`
+}
+
+func Shells() []string {
+	if shells := Get().Exec.Shells; len(shells) > 0 {
+		return shells
+	}
+	return DefaultShells
+}
+
+// ExecChoice is what was last chosen in the exec dialog for an image
+type ExecChoice struct {
+	Command    string   `yaml:"command,omitempty"`
+	User       string   `yaml:"user,omitempty"`
+	WorkingDir string   `yaml:"working_dir,omitempty"`
+	Env        []string `yaml:"env,omitempty"`
+	Privileged bool     `yaml:"privileged,omitempty"`
+}
+
+var exec_choices_file = &stateFile{name: "exec_choices.yaml"}
+
+func GetExecChoice(image string) (ExecChoice, bool) {
+	choices := make(map[string]ExecChoice)
+	if err := exec_choices_file.load(&choices); err != nil {
+		return ExecChoice{}, false
+	}
+	choice, ok := choices[image]
+	return choice, ok
+}
+
+func SaveExecChoice(image string, choice ExecChoice) error {
+	choices := make(map[string]ExecChoice)
+	return exec_choices_file.update(&choices, func() {
+		choices[image] = choice
+	})
+}
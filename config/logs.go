@@ -0,0 +1,27 @@
+package config
+
+const (
+	DefaultMaxLogLines  = 1000000
+	DefaultLogsMemoryMB = 64
+)
+
+// LogsConfig sizes the history of the logs window, lines beyond the memory limit are kept in temp files
+type LogsConfig struct {
+	MaxLines int `yaml:"max_lines"`
+	MemoryMB int `yaml:"memory_mb"`
+}
+
+func MaxLogLines() int {
+	if max_lines := Get().Logs.MaxLines; max_lines > 0 {
+		return max_lines
+	}
+	return DefaultMaxLogLines
+}
+
+// LogsMemoryLimit is the size of the logs kept in memory, in bytes
+func LogsMemoryLimit() int64 {
+	if memory_mb := Get().Logs.MemoryMB; memory_mb > 0 {
+		return int64(memory_mb) << 20
+	}
+	return DefaultLogsMemoryMB << 20
+}
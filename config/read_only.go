@@ -0,0 +1,17 @@
+package config
+
+import "errors"
+
+// ErrReadOnly is returned by every action that's disabled in read-only mode
+var ErrReadOnly = errors.New("dc-top is running in read-only mode")
+
+var is_read_only bool
+
+// SetReadOnly disables the actions that modify containers or the compose project, or run anything inside containers
+func SetReadOnly(read_only bool) {
+	is_read_only = read_only
+}
+
+func IsReadOnly() bool {
+	return is_read_only
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"path/filepath"
+)
+
+// RecordingsConfig sets where session recordings are saved
+type RecordingsConfig struct {
+	Dir string `yaml:"dir"`
+}
+
+// RecordingsDir defaults to a directory next to the default config file
+func RecordingsDir() string {
+	if dir := Get().Recordings.Dir; dir != "" {
+		return dir
+	}
+	config_path := DefaultPath()
+	if config_path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(config_path), "recordings")
+}
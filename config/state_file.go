@@ -0,0 +1,64 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// stateFile is a YAML file that dc-top saves its state in, it's next to the default config file even if another
+// config file is used
+type stateFile struct {
+	name string
+	lock sync.Mutex
+}
+
+func (file *stateFile) path() string {
+	config_path := DefaultPath()
+	if config_path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(config_path), file.name)
+}
+
+// load reads the file into state, state is left as is when the file doesn't exist
+func (file *stateFile) load(state interface{}) error {
+	file.lock.Lock()
+	defer file.lock.Unlock()
+	return file.read(state)
+}
+
+// update reads the file into state, applies change to it and writes it back
+func (file *stateFile) update(state interface{}, change func()) error {
+	file.lock.Lock()
+	defer file.lock.Unlock()
+	path := file.path()
+	if path == "" {
+		return errors.New("no config directory")
+	}
+	if err := file.read(state); err != nil {
+		return err
+	}
+	change()
+	contents, err := yaml.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, contents, 0644)
+}
+
+func (file *stateFile) read(state interface{}) error {
+	contents, err := os.ReadFile(file.path())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(contents, state)
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimeoutsConfig maps container names and labels ("key" or "key=value") to durations such as "45s"
+type TimeoutsConfig struct {
+	Default    string            `yaml:"default"`
+	Containers map[string]string `yaml:"containers"`
+	Labels     map[string]string `yaml:"labels"`
+}
+
+// StopTimeout returns the stop timeout configured for a container, container names take precedence over labels.
+// The default isn't returned, it only applies to containers without their own StopTimeout
+func StopTimeout(name string, labels map[string]string) (time.Duration, bool) {
+	timeouts := Get().StopTimeouts
+	if timeout, ok := timeouts.Containers[name]; ok {
+		return parseValidatedDuration(timeout), true
+	}
+	sorted_labels := make([]string, 0, len(timeouts.Labels))
+	for label := range timeouts.Labels {
+		sorted_labels = append(sorted_labels, label)
+	}
+	sort.Strings(sorted_labels)
+	for _, label := range sorted_labels {
+		key_value := strings.SplitN(label, "=", 2)
+		value, ok := labels[key_value[0]]
+		if ok && (len(key_value) == 1 || key_value[1] == value) {
+			return parseValidatedDuration(timeouts.Labels[label]), true
+		}
+	}
+	return 0, false
+}
+
+// DefaultStopTimeout returns the configured default stop timeout
+func DefaultStopTimeout() (time.Duration, bool) {
+	if timeout := Get().StopTimeouts.Default; timeout != "" {
+		return parseValidatedDuration(timeout), true
+	}
+	return 0, false
+}
+
+func (timeouts *TimeoutsConfig) validate() error {
+	all_timeouts := []string{}
+	if timeouts.Default != "" {
+		all_timeouts = append(all_timeouts, timeouts.Default)
+	}
+	for _, timeout := range timeouts.Containers {
+		all_timeouts = append(all_timeouts, timeout)
+	}
+	for _, timeout := range timeouts.Labels {
+		all_timeouts = append(all_timeouts, timeout)
+	}
+	for _, timeout := range all_timeouts {
+		if _, err := time.ParseDuration(timeout); err != nil {
+			return fmt.Errorf("bad stop timeout '%s' in config: %s", timeout, err)
+		}
+	}
+	return nil
+}
+
+// durations are validated when the config is loaded
+func parseValidatedDuration(duration string) time.Duration {
+	parsed, _ := time.ParseDuration(duration)
+	return parsed
+}
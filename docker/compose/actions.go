@@ -2,6 +2,7 @@ package compose
 
 import (
 	"context"
+	"dc-top/config"
 	"dc-top/utils"
 	"encoding/json"
 	"errors"
@@ -14,14 +15,23 @@ import (
 )
 
 func Up(ctx context.Context) ([]byte, error) {
+	if config.IsReadOnly() {
+		return []byte(config.ErrReadOnly.Error()), config.ErrReadOnly
+	}
 	return exec.CommandContext(ctx, "docker-compose", "--compatibility", "-f", DcYamlPath(), "up", "--remove-orphans").CombinedOutput()
 }
 
 func Down(ctx context.Context) ([]byte, error) {
+	if config.IsReadOnly() {
+		return []byte(config.ErrReadOnly.Error()), config.ErrReadOnly
+	}
 	return exec.CommandContext(ctx, "docker-compose", "--compatibility", "-f", DcYamlPath(), "down", "--remove-orphans").CombinedOutput()
 }
 
 func Restart(ctx context.Context) ([]byte, error) {
+	if config.IsReadOnly() {
+		return []byte(config.ErrReadOnly.Error()), config.ErrReadOnly
+	}
 	return exec.CommandContext(ctx, "docker-compose", "--compatibility", "-f", DcYamlPath(), "restart").CombinedOutput()
 }
 
@@ -0,0 +1,116 @@
+package compose
+
+import (
+	"context"
+	"dc-top/config"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ServiceSnippet renders a single service the way it would appear under `services:`
+func ServiceSnippet(name string, service Service) (string, error) {
+	snippet, err := yaml.Marshal(map[string]Service{name: service})
+	return string(snippet), err
+}
+
+// AddService inserts a new service into the docker-compose yaml, the file is restored if the result is invalid
+func AddService(ctx context.Context, name string, service Service) ([]byte, error) {
+	if config.IsReadOnly() {
+		return []byte(config.ErrReadOnly.Error()), config.ErrReadOnly
+	}
+	contents, err := os.ReadFile(DcYamlPath())
+	if err != nil {
+		return nil, err
+	}
+	names, err := serviceNames(contents)
+	if err != nil {
+		return nil, err
+	}
+	if names[name] {
+		return nil, fmt.Errorf("service '%s' already exists", name)
+	}
+	snippet, err := ServiceSnippet(name, service)
+	if err != nil {
+		return nil, err
+	}
+	if err = CreateBackupYaml(); err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(DcYamlPath(), insertService(string(contents), snippet), 0644); err != nil {
+		return nil, err
+	}
+	if !ValidateYaml(ctx) {
+		output, _ := Config(ctx)
+		RestoreFromBackup()
+		return output, fmt.Errorf("adding service '%s' made the docker-compose yaml invalid", name)
+	}
+	return nil, nil
+}
+
+// serviceNames returns the names of the services in the yaml without decoding the services, whose keys can have
+// any of the forms compose accepts
+func serviceNames(contents []byte) (map[string]bool, error) {
+	var dc_file struct {
+		Services map[string]interface{} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(contents, &dc_file); err != nil {
+		return nil, fmt.Errorf("failed to parse the docker-compose yaml: %s", err)
+	}
+	names := make(map[string]bool, len(dc_file.Services))
+	for name := range dc_file.Services {
+		names[name] = true
+	}
+	return names, nil
+}
+
+func insertService(contents string, snippet string) []byte {
+	lines := strings.Split(contents, "\n")
+	services_line := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "services:") {
+			services_line = i
+			break
+		}
+	}
+	if services_line == -1 {
+		if !strings.HasSuffix(contents, "\n") {
+			contents += "\n"
+		}
+		return []byte(contents + "services:\n" + indent(snippet, "  "))
+	}
+
+	// use the indentation of the first existing service
+	indentation := "  "
+	for _, line := range lines[services_line+1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			if len(trimmed) < len(line) {
+				indentation = line[:len(line)-len(trimmed)]
+			}
+			break
+		}
+	}
+
+	var new_contents strings.Builder
+	new_contents.WriteString(strings.Join(lines[:services_line+1], "\n"))
+	new_contents.WriteString("\n")
+	new_contents.WriteString(indent(snippet, indentation))
+	new_contents.WriteString(strings.Join(lines[services_line+1:], "\n"))
+	return []byte(new_contents.String())
+}
+
+// indent also converts the two-space nesting of the yaml encoder into the given indentation
+func indent(snippet string, indentation string) string {
+	var indented strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(snippet, "\n"), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		depth := (len(line) - len(trimmed)) / 2
+		indented.WriteString(strings.Repeat(indentation, depth+1))
+		indented.WriteString(trimmed)
+		indented.WriteString("\n")
+	}
+	return indented.String()
+}
@@ -0,0 +1,32 @@
+package compose
+
+import "testing"
+
+func TestServiceNamesAcceptsAllServiceForms(t *testing.T) {
+	contents := []byte(`services:
+  web:
+    image: node
+    command: "npm start"
+    environment:
+      NODE_ENV: production
+    ports:
+      - target: 80
+        published: 8080
+        protocol: tcp
+    networks:
+      front:
+        aliases:
+          - site
+  db:
+    image: postgres
+networks:
+  front:
+`)
+	names, err := serviceNames(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || !names["web"] || !names["db"] {
+		t.Errorf("expected web and db, got %v", names)
+	}
+}
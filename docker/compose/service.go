@@ -10,4 +10,11 @@ type Service struct {
 	ContainerName string   `yaml:"container_name,omitempty"`
 	Restart       string   `yaml:"restart,omitempty"`
 	Environment   []string `yaml:"environment,omitempty"`
+	Command       []string `yaml:"command,omitempty"`
+	Ports         []string `yaml:"ports,omitempty"`
+	Volumes       []string `yaml:"volumes,omitempty"`
+	NetworkMode   string   `yaml:"network_mode,omitempty"`
+	Networks      []string `yaml:"networks,omitempty"`
+	Cpus          string   `yaml:"cpus,omitempty"`
+	MemLimit      string   `yaml:"mem_limit,omitempty"`
 }
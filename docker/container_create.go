@@ -0,0 +1,173 @@
+package docker
+
+import (
+	"context"
+	"dc-top/config"
+	"dc-top/docker/compose"
+	"dc-top/utils"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+)
+
+// RunSpec describes a container the same way `docker run` flags do
+type RunSpec struct {
+	Image             string
+	Name              string
+	Command           []string
+	Env               []string
+	Ports             []string
+	Volumes           []string
+	Network           string
+	RestartPolicy     string
+	MaximumRetryCount int
+	NanoCPUs          int64
+	Memory            int64
+}
+
+var builtinNetworkModes = map[string]bool{
+	"bridge": true,
+	"host":   true,
+	"none":   true,
+}
+
+func ListImages(ctx context.Context) ([]string, error) {
+	images, err := docker_cli.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(images))
+	for _, image := range images {
+		for _, tag := range image.RepoTags {
+			if tag != "<none>:<none>" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// RunContainer creates and starts a container, the image has to exist locally
+func RunContainer(ctx context.Context, spec RunSpec) (string, error) {
+	if config.IsReadOnly() {
+		return "", config.ErrReadOnly
+	}
+	exposed_ports, port_bindings, err := nat.ParsePortSpecs(spec.Ports)
+	if err != nil {
+		return "", err
+	}
+	container_config := &container.Config{
+		Image:        spec.Image,
+		Cmd:          spec.Command,
+		Env:          spec.Env,
+		ExposedPorts: exposed_ports,
+	}
+	host_config := &container.HostConfig{
+		Binds:        spec.Volumes,
+		PortBindings: port_bindings,
+		NetworkMode:  container.NetworkMode(spec.Network),
+		RestartPolicy: container.RestartPolicy{
+			Name:              spec.RestartPolicy,
+			MaximumRetryCount: spec.MaximumRetryCount,
+		},
+		Resources: container.Resources{
+			NanoCPUs: spec.NanoCPUs,
+			Memory:   spec.Memory,
+		},
+	}
+	created, err := docker_cli.ContainerCreate(ctx, container_config, host_config, &network.NetworkingConfig{}, nil, spec.Name)
+	if err != nil {
+		return "", err
+	}
+	if err = docker_cli.ContainerStart(ctx, created.ID, types.ContainerStartOptions{}); err != nil {
+		return created.ID, err
+	}
+	return created.ID, nil
+}
+
+func (spec *RunSpec) DockerRunCommand() string {
+	args := []string{"docker", "run", "-d"}
+	if spec.Name != "" {
+		args = append(args, "--name", spec.Name)
+	}
+	for _, env := range spec.Env {
+		args = append(args, "-e", env)
+	}
+	for _, port := range spec.Ports {
+		args = append(args, "-p", port)
+	}
+	for _, volume := range spec.Volumes {
+		args = append(args, "-v", volume)
+	}
+	if spec.Network != "" {
+		args = append(args, "--network", spec.Network)
+	}
+	if spec.RestartPolicy != "" && spec.RestartPolicy != "no" {
+		args = append(args, "--restart", spec.restartPolicyString())
+	}
+	if spec.NanoCPUs != 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(float64(spec.NanoCPUs)/1e9, 'f', -1, 64))
+	}
+	if spec.Memory != 0 {
+		args = append(args, "--memory", units.BytesSize(float64(spec.Memory)))
+	}
+	args = append(args, spec.Image)
+	args = append(args, spec.Command...)
+	return utils.QuoteArgs(args)
+}
+
+func (spec *RunSpec) ComposeService() (string, compose.Service) {
+	service := compose.Service{
+		Image:         spec.Image,
+		ContainerName: spec.Name,
+		Environment:   spec.Env,
+		Command:       spec.Command,
+		Ports:         spec.Ports,
+		Volumes:       spec.Volumes,
+	}
+	if spec.RestartPolicy != "" && spec.RestartPolicy != "no" {
+		service.Restart = spec.restartPolicyString()
+	}
+	if builtinNetworkModes[spec.Network] {
+		service.NetworkMode = spec.Network
+	} else if spec.Network != "" {
+		service.Networks = []string{spec.Network}
+	}
+	if spec.NanoCPUs != 0 {
+		service.Cpus = strconv.FormatFloat(float64(spec.NanoCPUs)/1e9, 'f', -1, 64)
+	}
+	if spec.Memory != 0 {
+		service.MemLimit = strconv.FormatInt(spec.Memory, 10)
+	}
+	return spec.serviceName(), service
+}
+
+func (spec *RunSpec) restartPolicyString() string {
+	if spec.RestartPolicy == "on-failure" && spec.MaximumRetryCount > 0 {
+		return fmt.Sprintf("%s:%d", spec.RestartPolicy, spec.MaximumRetryCount)
+	}
+	return spec.RestartPolicy
+}
+
+// the container name, or the image name without registry and tag
+func (spec *RunSpec) serviceName() string {
+	if spec.Name != "" {
+		return spec.Name
+	}
+	name := spec.Image
+	if slash := strings.LastIndex(name, "/"); slash != -1 {
+		name = name[slash+1:]
+	}
+	if colon := strings.Index(name, ":"); colon != -1 {
+		name = name[:colon]
+	}
+	return name
+}
@@ -78,11 +78,15 @@ func UpdatedDatum(ctx context.Context, old_datum ContainerDatum, base types.Cont
 		}
 		return ContainerDatum{}, fmt.Errorf("2 Failed to get stats and container %s wasnt deleted. %s", old_datum.base.ID, err)
 	}
+	inspection := old_datum.inspection
+	if popStaleInspection(base.ID) {
+		inspection = InspectContainerNoPanic(ctx, base.ID)
+	}
 	return ContainerDatum{
 		base:         base,
 		stats_stream: old_datum.stats_stream,
 		cached_stats: new_stats,
-		inspection:   old_datum.inspection,
+		inspection:   inspection,
 		is_deleted:   false,
 	}, nil
 }
@@ -119,6 +123,17 @@ func (datum *ContainerDatum) InspectData() types.ContainerJSON {
 	return datum.inspection
 }
 
+// the label docker compose puts on the containers of a project
+const composeProjectLabel = "com.docker.compose.project"
+
+// ComposeProject is the compose project of the container, or empty if it wasn't created by docker compose
+func (datum *ContainerDatum) ComposeProject() string {
+	if datum.inspection.Config == nil {
+		return ""
+	}
+	return datum.inspection.Config.Labels[composeProjectLabel]
+}
+
 func (datum *ContainerDatum) Contains(substr string) bool {
 	return strings.Contains(datum.Image(), substr) || strings.Contains(datum.cached_stats.Name, substr)
 }
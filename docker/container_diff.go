@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"context"
+	"sort"
+)
+
+// ChangeKind is the kind of a change of the container's writable layer, with the values of the engine's API
+type ChangeKind uint8
+
+const (
+	ChangeModified ChangeKind = iota
+	ChangeAdded
+	ChangeDeleted
+)
+
+func (kind ChangeKind) String() string {
+	switch kind {
+	case ChangeModified:
+		return "C"
+	case ChangeAdded:
+		return "A"
+	case ChangeDeleted:
+		return "D"
+	}
+	return "?"
+}
+
+type ContainerChange struct {
+	Path string
+	Kind ChangeKind
+}
+
+// ContainerChanges lists the paths the container added, changed or deleted relative to its image, sorted by path
+func ContainerChanges(ctx context.Context, id string) ([]ContainerChange, error) {
+	items, err := docker_cli.ContainerDiff(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]ContainerChange, len(items))
+	for i, item := range items {
+		changes[i] = ContainerChange{Path: item.Path, Kind: ChangeKind(item.Kind)}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
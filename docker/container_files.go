@@ -0,0 +1,186 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ContainerFile is an entry of a container's filesystem, read through the archive API so it works without a shell
+// and for stopped containers
+type ContainerFile struct {
+	Path       string
+	Size       int64
+	Mode       os.FileMode
+	ModTime    time.Time
+	LinkTarget string
+}
+
+func (file *ContainerFile) Name() string {
+	return path.Base(file.Path)
+}
+
+func (file *ContainerFile) IsDir() bool {
+	return file.Mode.IsDir()
+}
+
+// ListDir lists the files in dir, the size of a subdirectory is the total size of the files under it.
+// The archive API can only copy whole trees, so listing a directory costs as much as reading everything under it,
+// but only the directory's own entries are kept. `progress` is called with the number of entries read so far
+func ListDir(ctx context.Context, id string, dir string, progress func(entries int)) ([]ContainerFile, error) {
+	dir = path.Clean("/" + dir)
+	reader, stat, err := docker_cli.CopyFromContainer(ctx, id, dir)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	if !stat.Mode.IsDir() {
+		return nil, fmt.Errorf("%s isn't a directory", dir)
+	}
+
+	files := make([]ContainerFile, 0)
+	// the index in files of each subdirectory, to add up the sizes under it
+	subdirs := make(map[string]int)
+	archive := tar.NewReader(reader)
+	for entries := 1; ; entries++ {
+		if entries%1000 == 0 {
+			progress(entries)
+		}
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		// entries are relative to the parent of dir, and start with its base name
+		entry_path := path.Join(path.Dir(dir), header.Name)
+		if entry_path == dir || !strings.HasPrefix(entry_path, strings.TrimSuffix(dir, "/")+"/") {
+			continue
+		}
+		relative := strings.TrimPrefix(entry_path, strings.TrimSuffix(dir, "/")+"/")
+		if slash := strings.Index(relative, "/"); slash >= 0 {
+			if i, ok := subdirs[relative[:slash]]; ok && header.Typeflag != tar.TypeDir {
+				files[i].Size += header.Size
+			}
+			continue
+		}
+		file := ContainerFile{
+			Path:       entry_path,
+			Size:       header.Size,
+			Mode:       header.FileInfo().Mode(),
+			ModTime:    header.ModTime,
+			LinkTarget: header.Linkname,
+		}
+		if file.IsDir() {
+			file.Size = 0
+			subdirs[relative] = len(files)
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].IsDir() != files[j].IsDir() {
+			return files[i].IsDir()
+		}
+		return files[i].Name() < files[j].Name()
+	})
+	return files, nil
+}
+
+// ReadContainerFile reads up to max_size bytes of a regular file, and reports whether it was truncated
+func ReadContainerFile(ctx context.Context, id string, file_path string, max_size int64) ([]byte, bool, error) {
+	reader, _, err := docker_cli.CopyFromContainer(ctx, id, file_path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer reader.Close()
+	archive := tar.NewReader(reader)
+	header, err := archive.Next()
+	if err != nil {
+		return nil, false, err
+	}
+	if header.Typeflag != tar.TypeReg {
+		return nil, false, fmt.Errorf("%s isn't a regular file", file_path)
+	}
+	contents, err := io.ReadAll(io.LimitReader(archive, max_size))
+	return contents, header.Size > max_size, err
+}
+
+// DownloadFromContainer copies a file or directory of the container into host_dir, like `docker cp`.
+// It returns the path of the copy
+func DownloadFromContainer(ctx context.Context, id string, container_path string, host_dir string) (string, error) {
+	reader, _, err := docker_cli.CopyFromContainer(ctx, id, container_path)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	if err = os.MkdirAll(host_dir, 0755); err != nil {
+		return "", err
+	}
+	root, err := filepath.Abs(host_dir)
+	if err != nil {
+		return "", err
+	}
+
+	// symlinks are created last, so nothing in the archive can be written through them
+	type symlink struct{ target, path string }
+	var symlinks []symlink
+	var copied string
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return "", err
+		}
+		target := filepath.Join(root, filepath.FromSlash(header.Name))
+		if target != root && !strings.HasPrefix(target, root+string(os.PathSeparator)) {
+			return "", fmt.Errorf("bad path in archive: %s", header.Name)
+		}
+		if copied == "" {
+			copied = target
+		}
+		mode := header.FileInfo().Mode().Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, mode|0700); err != nil {
+				return "", err
+			}
+		case tar.TypeReg:
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return "", err
+			}
+			if err = writeFile(target, archive, mode); err != nil {
+				return "", err
+			}
+		case tar.TypeSymlink:
+			symlinks = append(symlinks, symlink{header.Linkname, target})
+		}
+	}
+	for _, link := range symlinks {
+		if err = os.Symlink(link.target, link.path); err != nil && !errors.Is(err, os.ErrExist) {
+			return "", err
+		}
+	}
+	return copied, nil
+}
+
+func writeFile(target string, contents io.Reader, mode os.FileMode) error {
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(file, contents); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
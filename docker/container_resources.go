@@ -0,0 +1,98 @@
+package docker
+
+import (
+	"context"
+	"dc-top/config"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+type ResourceLimits struct {
+	NanoCPUs          int64
+	Memory            int64
+	MemorySwap        int64
+	CPUShares         int64
+	PidsLimit         int64
+	RestartPolicy     string
+	MaximumRetryCount int
+}
+
+// ResourceLimitsUpdate holds the limits to change, fields left at their zero value (nil PidsLimit, empty
+// RestartPolicy) aren't changed
+type ResourceLimitsUpdate struct {
+	NanoCPUs          int64
+	Memory            int64
+	MemorySwap        int64
+	CPUShares         int64
+	PidsLimit         *int64
+	RestartPolicy     string
+	MaximumRetryCount int
+}
+
+var (
+	stale_inspections      = make(map[string]bool)
+	stale_inspections_lock sync.Mutex
+)
+
+func GetResourceLimits(ctx context.Context, id string) (ResourceLimits, error) {
+	inspection, err := docker_cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return ResourceLimits{}, err
+	}
+	host_config := inspection.HostConfig
+	var pids_limit int64
+	if host_config.PidsLimit != nil {
+		pids_limit = *host_config.PidsLimit
+	}
+	return ResourceLimits{
+		NanoCPUs:          host_config.NanoCPUs,
+		Memory:            host_config.Memory,
+		MemorySwap:        host_config.MemorySwap,
+		CPUShares:         host_config.CPUShares,
+		PidsLimit:         pids_limit,
+		RestartPolicy:     host_config.RestartPolicy.Name,
+		MaximumRetryCount: host_config.RestartPolicy.MaximumRetryCount,
+	}, nil
+}
+
+// UpdateResourceLimits changes the limits of a container without recreating it, and returns the engine's warnings
+func UpdateResourceLimits(ctx context.Context, id string, limits ResourceLimitsUpdate) ([]string, error) {
+	if config.IsReadOnly() {
+		return nil, config.ErrReadOnly
+	}
+	update_config := container.UpdateConfig{
+		Resources: container.Resources{
+			NanoCPUs:   limits.NanoCPUs,
+			Memory:     limits.Memory,
+			MemorySwap: limits.MemorySwap,
+			CPUShares:  limits.CPUShares,
+			PidsLimit:  limits.PidsLimit,
+		},
+		RestartPolicy: container.RestartPolicy{
+			Name:              limits.RestartPolicy,
+			MaximumRetryCount: limits.MaximumRetryCount,
+		},
+	}
+	response, err := docker_cli.ContainerUpdate(ctx, id, update_config)
+	if err != nil {
+		return nil, err
+	}
+	markInspectionStale(id)
+	return response.Warnings, nil
+}
+
+// the cached inspection of a container is refreshed on the next data update after it was marked as stale
+func markInspectionStale(id string) {
+	stale_inspections_lock.Lock()
+	defer stale_inspections_lock.Unlock()
+	stale_inspections[id] = true
+}
+
+func popStaleInspection(id string) bool {
+	stale_inspections_lock.Lock()
+	defer stale_inspections_lock.Unlock()
+	is_stale := stale_inspections[id]
+	delete(stale_inspections, id)
+	return is_stale
+}
@@ -0,0 +1,128 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"dc-top/config"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ContainerProcess is a process of a container as reported by the host's ps, so PID is in the host's namespace
+type ContainerProcess struct {
+	PID     int
+	User    string
+	CPU     float64
+	Memory  float64
+	RSS     int64
+	Command string
+}
+
+// the ps arguments of ContainerTop, the engine falls back to `-ef` when they aren't supported
+var topArguments = []string{"-eo", "pid,user,pcpu,pmem,rss,args"}
+
+// ContainerTop lists the processes of a running container through the engine, which runs ps on the host
+func ContainerTop(ctx context.Context, id string) ([]ContainerProcess, error) {
+	top, err := docker_cli.ContainerTop(ctx, id, topArguments)
+	if err != nil {
+		if top, err = docker_cli.ContainerTop(ctx, id, nil); err != nil {
+			return nil, err
+		}
+	}
+	columns := make(map[string]int)
+	for i, title := range top.Titles {
+		columns[title] = i
+	}
+	column := func(row []string, titles ...string) string {
+		for _, title := range titles {
+			if i, ok := columns[title]; ok && i < len(row) {
+				return row[i]
+			}
+		}
+		return ""
+	}
+	processes := make([]ContainerProcess, 0, len(top.Processes))
+	for _, row := range top.Processes {
+		pid, err := strconv.Atoi(column(row, "PID"))
+		if err != nil {
+			continue
+		}
+		cpu, _ := strconv.ParseFloat(column(row, "%CPU", "C", "CPU"), 64)
+		memory, _ := strconv.ParseFloat(column(row, "%MEM"), 64)
+		rss, _ := strconv.ParseInt(column(row, "RSS"), 10, 64)
+		processes = append(processes, ContainerProcess{
+			PID:     pid,
+			User:    column(row, "USER", "UID"),
+			CPU:     cpu,
+			Memory:  memory,
+			RSS:     rss * 1024,
+			Command: column(row, "COMMAND", "CMD", "Name"),
+		})
+	}
+	return processes, nil
+}
+
+// SignalProcess sends a signal to a single process of the container by exec'ing kill as root inside it.
+// The process is identified by its host PID and command line as listed by ContainerTop
+func SignalProcess(ctx context.Context, id string, process ContainerProcess, signal string) error {
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
+	pid, err := containerPid(ctx, id, process)
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	command := fmt.Sprintf("kill -s %s %d", strings.TrimPrefix(signal, "SIG"), pid)
+	exit_code, err := runCommand(ctx, id, types.ExecConfig{User: "0", Cmd: []string{"sh", "-c", command}}, &bytes.Buffer{}, &stderr)
+	if err != nil {
+		return err
+	}
+	if exit_code != 0 {
+		return fmt.Errorf("'%s' failed: %s", command, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// containerPid translates a host PID to the container's PID namespace. The host's /proc has the translation
+// when dc-top runs on the docker host, otherwise the process is found by its command line inside the container
+func containerPid(ctx context.Context, id string, process ContainerProcess) (int, error) {
+	// with a remote engine the local /proc belongs to another machine, so the command line has to match too
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", process.PID))
+	is_local := err == nil && strings.TrimSpace(string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '}))) == process.Command
+	if status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", process.PID)); is_local && err == nil {
+		scanner := bufio.NewScanner(bytes.NewReader(status))
+		for scanner.Scan() {
+			if fields := strings.Fields(scanner.Text()); len(fields) > 1 && fields[0] == "NSpid:" {
+				return strconv.Atoi(fields[len(fields)-1])
+			}
+		}
+	}
+
+	var stdout bytes.Buffer
+	list_processes := `for p in /proc/[0-9]*; do echo "${p#/proc/} $(tr '\0' ' ' < $p/cmdline)"; done`
+	if _, err := runCommand(ctx, id, types.ExecConfig{User: "0", Cmd: []string{"sh", "-c", list_processes}}, &stdout, &bytes.Buffer{}); err != nil {
+		return 0, err
+	}
+	pid := 0
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 2)
+		if len(fields) != 2 || strings.TrimSpace(fields[1]) != process.Command {
+			continue
+		}
+		if pid != 0 {
+			return 0, fmt.Errorf("several processes run '%s', can't tell which one is %d", process.Command, process.PID)
+		}
+		pid, _ = strconv.Atoi(fields[0])
+	}
+	if pid == 0 {
+		return 0, fmt.Errorf("didn't find process %d inside the container", process.PID)
+	}
+	return pid, nil
+}
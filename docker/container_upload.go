@@ -0,0 +1,138 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"dc-top/config"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// EditableFile is a regular file of a container read for editting, its header keeps the ownership and mode
+// so they're preserved when it's written back
+type EditableFile struct {
+	Path   string
+	header tar.Header
+}
+
+// ReadEditableFile reads a whole regular file of the container
+func ReadEditableFile(ctx context.Context, id string, file_path string) (EditableFile, []byte, error) {
+	reader, _, err := docker_cli.CopyFromContainer(ctx, id, file_path)
+	if err != nil {
+		return EditableFile{}, nil, err
+	}
+	defer reader.Close()
+	archive := tar.NewReader(reader)
+	header, err := archive.Next()
+	if err != nil {
+		return EditableFile{}, nil, err
+	}
+	if header.Typeflag != tar.TypeReg {
+		return EditableFile{}, nil, fmt.Errorf("%s isn't a regular file", file_path)
+	}
+	contents, err := io.ReadAll(archive)
+	if err != nil {
+		return EditableFile{}, nil, err
+	}
+	return EditableFile{Path: path.Clean(file_path), header: *header}, contents, nil
+}
+
+// WriteContainerFile replaces the contents of a file read by ReadEditableFile, keeping its owner and mode
+func WriteContainerFile(ctx context.Context, id string, file EditableFile, contents []byte) error {
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
+	header := file.header
+	header.Name = path.Base(file.Path)
+	header.Size = int64(len(contents))
+	header.ModTime = time.Now()
+	header.AccessTime, header.ChangeTime = time.Time{}, time.Time{}
+	// PAX records of the original file may hold its old size
+	header.PAXRecords = nil
+	header.Format = tar.FormatUnknown
+
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+	if err := writer.WriteHeader(&header); err != nil {
+		return err
+	}
+	if _, err := writer.Write(contents); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	// the header already has the original uid and gid, CopyUIDGID would chown the file to the container's user
+	return docker_cli.CopyToContainer(ctx, id, path.Dir(file.Path), &archive, types.CopyToContainerOptions{CopyUIDGID: false})
+}
+
+// UploadToContainer copies a host file or directory into container_dir, like `docker cp -a`.
+// The copy is owned by the user the container is configured to run as, or by root if it has none
+func UploadToContainer(ctx context.Context, id string, host_path string, container_dir string) error {
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
+	host_path = filepath.Clean(host_path)
+	if _, err := os.Lstat(host_path); err != nil {
+		return err
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeArchive(writer, host_path))
+	}()
+	defer reader.Close()
+	return docker_cli.CopyToContainer(ctx, id, container_dir, reader, types.CopyToContainerOptions{CopyUIDGID: true})
+}
+
+// writeArchive writes the tree under host_path as a tar archive, with names relative to its parent
+func writeArchive(output io.Writer, host_path string) error {
+	archive := tar.NewWriter(output)
+	parent := filepath.Dir(host_path)
+	err := filepath.Walk(host_path, func(file_path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file_path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(parent, file_path)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err = archive.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		file, err := os.Open(file_path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(archive, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return archive.Close()
+}
@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"context"
+	"dc-top/config"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+const DebugTargetLabel = "dc-top.debug-target"
+
+// OpenDebugShell starts a throwaway container that shares the PID and network namespaces of the target
+// and attaches to its shell. This works on containers that don't have a shell of their own.
+// The sidecar has to be removed with RemoveDebugSidecar once the shell isn't needed.
+// The image is pulled if it's missing, which may take a while, on_progress gets the progress of the pull
+func OpenDebugShell(ctx context.Context, target_id string, image string, shell string, on_progress func(string)) (*types.HijackedResponse, string, error) {
+	if config.IsReadOnly() {
+		return nil, "", config.ErrReadOnly
+	}
+	if err := pullIfMissing(ctx, image, on_progress); err != nil {
+		return nil, "", err
+	}
+	namespace := "container:" + target_id
+	container_config := &container.Config{
+		Image:        image,
+		Cmd:          []string{shell},
+		Tty:          true,
+		OpenStdin:    true,
+		StdinOnce:    true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Labels:       map[string]string{DebugTargetLabel: target_id},
+	}
+	host_config := &container.HostConfig{
+		PidMode:     container.PidMode(namespace),
+		NetworkMode: container.NetworkMode(namespace),
+	}
+	created, err := docker_cli.ContainerCreate(ctx, container_config, host_config, &network.NetworkingConfig{}, nil, "")
+	if err != nil {
+		return nil, "", err
+	}
+	highjacked_conn, err := docker_cli.ContainerAttach(ctx, created.ID, types.ContainerAttachOptions{
+		Stream: true,
+		Stdin:  true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		RemoveDebugSidecar(created.ID)
+		return nil, "", err
+	}
+	if err = docker_cli.ContainerStart(ctx, created.ID, types.ContainerStartOptions{}); err != nil {
+		highjacked_conn.Close()
+		RemoveDebugSidecar(created.ID)
+		return nil, "", err
+	}
+	log.Printf("Started debug sidecar %s (%s) for container '%s'\n", created.ID, image, target_id)
+	return &highjacked_conn, created.ID, nil
+}
+
+// RemoveDebugSidecar doesn't take a context because it's called after the shell's context was cancelled
+func RemoveDebugSidecar(sidecar_id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := docker_cli.ContainerRemove(ctx, sidecar_id, types.ContainerRemoveOptions{Force: true})
+	if err != nil {
+		log.Printf("Failed to remove debug sidecar %s: %s", sidecar_id, err)
+	}
+}
+
+func pullIfMissing(ctx context.Context, image string, on_progress func(string)) error {
+	_, _, err := docker_cli.ImageInspectWithRaw(ctx, image)
+	if err == nil || !client.IsErrNotFound(err) {
+		return err
+	}
+	log.Printf("Pulling debug image %s", image)
+	on_progress(fmt.Sprintf("Pulling %s...", image))
+	progress, err := docker_cli.ImagePull(ctx, image, types.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+	defer progress.Close()
+	// the pull reports its progress as a stream of json messages
+	decoder := json.NewDecoder(progress)
+	for {
+		var message jsonmessage.JSONMessage
+		if err := decoder.Decode(&message); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if message.Error != nil {
+			return message.Error
+		}
+		status := fmt.Sprintf("Pulling %s: %s", image, message.Status)
+		if message.ID != "" {
+			status = fmt.Sprintf("Pulling %s: %s %s", image, message.ID, message.Status)
+		}
+		if message.Progress != nil && message.Progress.Total > 0 {
+			status += fmt.Sprintf(" %d%%", message.Progress.Current*100/message.Progress.Total)
+		}
+		on_progress(status)
+	}
+}
@@ -2,56 +2,122 @@ package docker
 
 import (
 	"context"
+	"dc-top/config"
 	"fmt"
 	"io"
 	"log"
-	"time"
 
 	"github.com/docker/docker/api/types"
 )
 
-const (
-	MaxSavedLogs = 1000
-)
+func StartContainer(ctx context.Context, id string) error {
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
+	return docker_cli.ContainerStart(ctx, id, types.ContainerStartOptions{})
+}
 
 func PauseContainer(ctx context.Context, id string) error {
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
 	return docker_cli.ContainerPause(ctx, id)
 }
 
 func UnpauseContainer(ctx context.Context, id string) error {
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
 	return docker_cli.ContainerUnpause(ctx, id)
 }
 
 func StopContainer(ctx context.Context, id string) error {
-	duration := 3 * time.Second
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
+	duration := StopTimeout(ctx, id)
 	return docker_cli.ContainerStop(ctx, id, &duration)
 }
 
 func RestartContainer(ctx context.Context, id string) error {
-	duration := 10 * time.Second
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
+	duration := StopTimeout(ctx, id)
 	return docker_cli.ContainerRestart(ctx, id, &duration)
 }
 
+func KillContainer(ctx context.Context, id string, signal string) error {
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
+	return docker_cli.ContainerKill(ctx, id, signal)
+}
+
+// SendStopSignal asks the container to stop by sending its stop signal, without escalating to SIGKILL
+func SendStopSignal(ctx context.Context, id string) error {
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
+	return docker_cli.ContainerKill(ctx, id, StopSignal(ctx, id))
+}
+
+func RenameContainer(ctx context.Context, id string, new_name string) error {
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
+	return docker_cli.ContainerRename(ctx, id, new_name)
+}
+
 func DeleteContainer(ctx context.Context, id string) error {
+	if config.IsReadOnly() {
+		return config.ErrReadOnly
+	}
 	return docker_cli.ContainerRemove(ctx, id,
 		types.ContainerRemoveOptions{RemoveVolumes: true, RemoveLinks: false, Force: true})
 }
 
-func StreamContainerLogs(id string, writer io.Writer, ctx context.Context, cancel context.CancelFunc) {
-	reader, err := docker_cli.ContainerLogs(ctx, id, types.ContainerLogsOptions{
+// StreamContainerLogs follows the timestamped logs of a container, decoding them by whether the container has a TTY.
+// Without a range only the last lines the logs window keeps (config.MaxLogLines) are read
+func StreamContainerLogs(id string, writer LogLinesWriter, logs_range LogsRange, ctx context.Context, cancel context.CancelFunc) {
+	inspection, err := docker_cli.ContainerInspect(ctx, id)
+	if err != nil {
+		log.Println(err)
+		// the stream is cancelled on purpose when it's replaced
+		if ctx.Err() == nil {
+			cancel()
+		}
+		return
+	}
+	decoder := NewLogDecoder(inspection.Config != nil && inspection.Config.Tty, true, writer)
+	options := types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
-		Tail:       fmt.Sprintf("%d", MaxSavedLogs),
+		Timestamps: true,
+		Tail:       fmt.Sprintf("%d", config.MaxLogLines()),
 		Follow:     true,
-	})
+	}
+	if logs_range.IsSet() {
+		options.Tail = "all"
+	}
+	if !logs_range.Since.IsZero() {
+		options.Since = fmt.Sprintf("%d", logs_range.Since.Unix())
+	}
+	if !logs_range.Until.IsZero() {
+		options.Until = fmt.Sprintf("%d", logs_range.Until.Unix())
+	}
+	reader, err := docker_cli.ContainerLogs(ctx, id, options)
 	if err != nil {
 		log.Println(err)
-		cancel()
+		if ctx.Err() == nil {
+			cancel()
+		}
 		return
 	}
 	defer reader.Close()
 
-	_, err = io.Copy(writer, reader)
+	_, err = io.Copy(decoder, reader)
+	decoder.Flush()
 	if err != nil && err != io.EOF && err.Error() != "context canceled" {
 		log.Println(err)
 		cancel()
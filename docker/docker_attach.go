@@ -0,0 +1,43 @@
+package docker
+
+import (
+	"context"
+	"dc-top/config"
+	"log"
+
+	"github.com/docker/docker/api/types"
+)
+
+// AttachedContainer is a connection to the stdio of a container's main process
+type AttachedContainer struct {
+	Conn *types.HijackedResponse
+	// without a TTY stdout and stderr are multiplexed and have to be demultiplexed with stdcopy
+	IsTty bool
+	// input can only be sent to containers that were created with an open stdin (`docker run -i`)
+	IsStdinOpen bool
+}
+
+func AttachContainer(ctx context.Context, id string) (*AttachedContainer, error) {
+	if config.IsReadOnly() {
+		return nil, config.ErrReadOnly
+	}
+	inspection, err := docker_cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	highjacked_conn, err := docker_cli.ContainerAttach(ctx, id, types.ContainerAttachOptions{
+		Stream: true,
+		Stdin:  inspection.Config.OpenStdin,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Attached to container '%s' (tty: %t, stdin: %t)", id, inspection.Config.Tty, inspection.Config.OpenStdin)
+	return &AttachedContainer{
+		Conn:        &highjacked_conn,
+		IsTty:       inspection.Config.Tty,
+		IsStdinOpen: inspection.Config.OpenStdin,
+	}, nil
+}
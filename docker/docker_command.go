@@ -0,0 +1,48 @@
+package docker
+
+import (
+	"context"
+	"dc-top/config"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// RunCommand runs a command without a TTY, so stdout and stderr arrive multiplexed and can be told apart.
+// It returns the exit code once the output ends
+func RunCommand(ctx context.Context, id string, command []string, stdout, stderr io.Writer) (int, error) {
+	if config.IsReadOnly() {
+		return 0, config.ErrReadOnly
+	}
+	return runCommand(ctx, id, types.ExecConfig{Cmd: command}, stdout, stderr)
+}
+
+func runCommand(ctx context.Context, id string, config types.ExecConfig, stdout, stderr io.Writer) (int, error) {
+	config.AttachStdout = true
+	config.AttachStderr = true
+	exec_id, err := docker_cli.ContainerExecCreate(ctx, id, config)
+	if err != nil {
+		return 0, err
+	}
+	highjacked_conn, err := docker_cli.ContainerExecAttach(ctx, exec_id.ID, types.ExecStartCheck{})
+	if err != nil {
+		return 0, err
+	}
+	defer highjacked_conn.Close()
+
+	copy_done := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(stdout, stderr, highjacked_conn.Reader)
+		copy_done <- err
+	}()
+	select {
+	case err = <-copy_done:
+		if err != nil {
+			return 0, err
+		}
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	return waitForExec(ctx, exec_id.ID)
+}
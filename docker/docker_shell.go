@@ -2,6 +2,7 @@ package docker
 
 import (
 	"context"
+	"dc-top/config"
 	"errors"
 	"fmt"
 	"log"
@@ -10,48 +11,122 @@ import (
 	"github.com/docker/docker/api/types"
 )
 
-func OpenShell(id string, ctx context.Context, shell string) (*types.HijackedResponse, error) {
+// ExecSpec describes a process started inside a running container, an empty command means a shell should be detected
+type ExecSpec struct {
+	Command    []string
+	User       string
+	WorkingDir string
+	Env        []string
+	Privileged bool
+}
+
+// OpenExec returns the connection to the process and the exec's id, which is needed for resizing its TTY
+func OpenExec(ctx context.Context, id string, spec ExecSpec) (*types.HijackedResponse, string, error) {
+	if config.IsReadOnly() {
+		return nil, "", config.ErrReadOnly
+	}
 	var cfg = types.ExecConfig{
+		User:         spec.User,
+		Privileged:   spec.Privileged,
 		Tty:          true,
 		AttachStdin:  true,
 		AttachStderr: true,
 		AttachStdout: true,
-		Cmd:          []string{shell},
+		Env:          spec.Env,
+		WorkingDir:   spec.WorkingDir,
+		Cmd:          spec.Command,
 	}
 	shell_ctx, shell_cancel := context.WithCancel(ctx)
 	defer shell_cancel()
 
 	exec_id, err := docker_cli.ContainerExecCreate(shell_ctx, id, cfg)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	highjacked_conn, err := docker_cli.ContainerExecAttach(shell_ctx, exec_id.ID, types.ExecStartCheck{Tty: true})
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	err = readinessChecker(shell_ctx, exec_id.ID)
 	if err != nil {
-		return nil, err
+		highjacked_conn.Close()
+		return nil, "", err
 	}
 
-	log.Printf("Using %s inside container '%s'\n\r", shell, id)
-	return &highjacked_conn, nil
+	log.Printf("Running %v inside container '%s'\n\r", spec.Command, id)
+	return &highjacked_conn, exec_id.ID, nil
+}
+
+// DetectShell returns the first of the shells that can be run inside the container
+func DetectShell(ctx context.Context, id string, shells []string) (string, error) {
+	for _, shell := range shells {
+		exec_id, err := docker_cli.ContainerExecCreate(ctx, id, types.ExecConfig{Cmd: []string{shell, "-c", "exit 0"}})
+		if err != nil {
+			return "", err
+		}
+		if err = docker_cli.ContainerExecStart(ctx, exec_id.ID, types.ExecStartCheck{Detach: true}); err != nil {
+			continue
+		}
+		exit_code, err := waitForExec(ctx, exec_id.ID)
+		if err != nil {
+			return "", err
+		}
+		if exit_code == 0 {
+			log.Printf("Detected shell %s inside container '%s'", shell, id)
+			return shell, nil
+		}
+	}
+	return "", fmt.Errorf("none of %v exist inside the container", shells)
+}
+
+// ExecExitCode returns the exit code of a finished exec
+func ExecExitCode(ctx context.Context, exec_id string) (int, error) {
+	return waitForExec(ctx, exec_id)
 }
 
+func ResizeExecTTY(ctx context.Context, exec_id string, width, height int) error {
+	return docker_cli.ContainerExecResize(ctx, exec_id, types.ResizeOptions{Width: uint(width), Height: uint(height)})
+}
+
+// ResizeContainerTTY resizes the TTY of a container's main process, used by attached sessions
+func ResizeContainerTTY(ctx context.Context, id string, width, height int) error {
+	return docker_cli.ContainerResize(ctx, id, types.ResizeOptions{Width: uint(width), Height: uint(height)})
+}
+
+func waitForExec(ctx context.Context, exec_id string) (int, error) {
+	for {
+		exec_inspect, err := docker_cli.ContainerExecInspect(ctx, exec_id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to inspect exec %s", exec_id)
+		}
+		if !exec_inspect.Running {
+			return exec_inspect.ExitCode, nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+// readinessChecker waits for the process to start, commands that already finished successfully are fine
 func readinessChecker(context context.Context, exec_id string) error {
+	deadline := time.Now().Add(5 * time.Second)
 	for {
 		exec_inspect, err := docker_cli.ContainerExecInspect(context, exec_id)
 		if err != nil {
 			return fmt.Errorf("failed to inspect exec %s", exec_id)
 		}
-		if exec_inspect.ExitCode != 0 || !exec_inspect.Running {
+		if exec_inspect.ExitCode != 0 && !exec_inspect.Running {
 			return errors.New("invalid entrypoint")
 		}
-		if exec_inspect.Pid == 0 {
-			time.Sleep(5 * time.Millisecond)
-			continue
+		if exec_inspect.Pid != 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("exec didn't start")
 		}
-		break
+		time.Sleep(5 * time.Millisecond)
 	}
-	return nil
 }
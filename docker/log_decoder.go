@@ -0,0 +1,143 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+// LogLine is a line of a container's output, Time is set when the stream has timestamps
+type LogLine struct {
+	Time     time.Time
+	Text     string
+	IsStderr bool
+}
+
+// LogLinesWriter receives the lines decoded from a log stream, in order
+type LogLinesWriter interface {
+	WriteLines(lines []LogLine)
+}
+
+// the header the engine puts before every frame of a non TTY stream, see stdcopy
+const (
+	frameHeaderLen  = 8
+	frameStreamByte = 0
+	frameSizeIndex  = 4
+
+	frameStdin  = 0
+	frameStdout = 1
+	frameStderr = 2
+	frameSystem = 3
+)
+
+// lines without a newline, like progress bars redrawn with \r, are cut at this length
+const maxLogLineLen = 64 * 1024
+
+// LogDecoder splits a log stream into lines. Containers without a TTY have their output multiplexed into frames,
+// with TTY it's a raw stream where everything is stdout. Frames and lines may be split across Write calls
+type LogDecoder struct {
+	is_tty         bool
+	has_timestamps bool
+	output         LogLinesWriter
+	// an incomplete frame of a multiplexed stream
+	frame []byte
+	// the incomplete last line of each stream
+	partial [2][]byte
+}
+
+// NewLogDecoder decodes a stream requested with or without timestamps, which the engine puts at the start of
+// every line
+func NewLogDecoder(is_tty bool, has_timestamps bool, output LogLinesWriter) *LogDecoder {
+	return &LogDecoder{is_tty: is_tty, has_timestamps: has_timestamps, output: output}
+}
+
+func (decoder *LogDecoder) Write(p []byte) (int, error) {
+	var lines []LogLine
+	if decoder.is_tty {
+		lines = decoder.appendLines(lines, p, false)
+	} else {
+		lines = decoder.decodeFrames(lines, p)
+	}
+	if len(lines) > 0 {
+		decoder.output.WriteLines(lines)
+	}
+	return len(p), nil
+}
+
+// Flush writes the incomplete last lines once the stream ended
+func (decoder *LogDecoder) Flush() {
+	var lines []LogLine
+	for stream, partial := range decoder.partial {
+		if len(partial) > 0 {
+			lines = append(lines, decoder.newLogLine(partial, stream == 1))
+			decoder.partial[stream] = nil
+		}
+	}
+	if len(lines) > 0 {
+		decoder.output.WriteLines(lines)
+	}
+}
+
+func (decoder *LogDecoder) decodeFrames(lines []LogLine, p []byte) []LogLine {
+	data := p
+	if len(decoder.frame) > 0 {
+		data = append(decoder.frame, p...)
+	}
+	for len(data) >= frameHeaderLen {
+		size := int(binary.BigEndian.Uint32(data[frameSizeIndex:frameHeaderLen]))
+		if len(data) < frameHeaderLen+size {
+			break
+		}
+		payload := data[frameHeaderLen : frameHeaderLen+size]
+		switch data[frameStreamByte] {
+		case frameStdout, frameStdin:
+			lines = decoder.appendLines(lines, payload, false)
+		case frameStderr, frameSystem:
+			lines = decoder.appendLines(lines, payload, true)
+		}
+		data = data[frameHeaderLen+size:]
+	}
+	decoder.frame = append([]byte(nil), data...)
+	return lines
+}
+
+func (decoder *LogDecoder) appendLines(lines []LogLine, payload []byte, is_stderr bool) []LogLine {
+	stream := 0
+	if is_stderr {
+		stream = 1
+	}
+	for {
+		end := bytes.IndexByte(payload, '\n')
+		if end < 0 {
+			break
+		}
+		line := payload[:end]
+		if partial := decoder.partial[stream]; len(partial) > 0 {
+			line = append(partial, line...)
+			decoder.partial[stream] = nil
+		}
+		lines = append(lines, decoder.newLogLine(line, is_stderr))
+		payload = payload[end+1:]
+	}
+	if len(payload) > 0 {
+		decoder.partial[stream] = append(decoder.partial[stream], payload...)
+	}
+	for len(decoder.partial[stream]) >= maxLogLineLen {
+		lines = append(lines, decoder.newLogLine(decoder.partial[stream][:maxLogLineLen], is_stderr))
+		decoder.partial[stream] = append([]byte(nil), decoder.partial[stream][maxLogLineLen:]...)
+	}
+	return lines
+}
+
+func (decoder *LogDecoder) newLogLine(line []byte, is_stderr bool) LogLine {
+	text := strings.TrimSuffix(string(line), "\r")
+	if decoder.has_timestamps {
+		if end := strings.IndexByte(text, ' '); end > 0 {
+			if timestamp, err := time.Parse(time.RFC3339Nano, text[:end]); err == nil {
+				return LogLine{Time: timestamp, Text: text[end+1:], IsStderr: is_stderr}
+			}
+		}
+	}
+	return LogLine{Text: text, IsStderr: is_stderr}
+}
@@ -0,0 +1,113 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+type collectedLines struct {
+	lines []LogLine
+}
+
+func (collected *collectedLines) WriteLines(lines []LogLine) {
+	collected.lines = append(collected.lines, lines...)
+}
+
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, frameHeaderLen)
+	header[frameStreamByte] = stream
+	binary.BigEndian.PutUint32(header[frameSizeIndex:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+// decodeInChunks feeds the stream to a decoder `chunk` bytes at a time
+func decodeInChunks(is_tty bool, stream []byte, chunk int) []LogLine {
+	collected := &collectedLines{}
+	decoder := NewLogDecoder(is_tty, false, collected)
+	for len(stream) > 0 {
+		n := chunk
+		if n > len(stream) {
+			n = len(stream)
+		}
+		decoder.Write(stream[:n])
+		stream = stream[n:]
+	}
+	decoder.Flush()
+	return collected.lines
+}
+
+func assertLogLines(t *testing.T, actual []LogLine, expected ...LogLine) {
+	t.Helper()
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d lines %v, got %d lines %v", len(expected), expected, len(actual), actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("line %d: expected %+v, got %+v", i, expected[i], actual[i])
+		}
+	}
+}
+
+func TestLogDecoderTTYKeepsWholeLines(t *testing.T) {
+	stream := []byte("12345678 first\r\nsecond line\nunterminated")
+	for _, chunk := range []int{1, 3, 8, len(stream)} {
+		assertLogLines(t, decodeInChunks(true, stream, chunk),
+			LogLine{Text: "12345678 first"},
+			LogLine{Text: "second line"},
+			LogLine{Text: "unterminated"})
+	}
+}
+
+func TestLogDecoderMultiplexedFragments(t *testing.T) {
+	var stream []byte
+	stream = append(stream, frame(frameStdout, "hello wo")...)
+	stream = append(stream, frame(frameStderr, "oops\n")...)
+	stream = append(stream, frame(frameStdout, "rld\nsecond\n")...)
+	stream = append(stream, frame(frameStdout, "")...)
+	stream = append(stream, frame(frameStderr, "last")...)
+	// every chunk size splits headers and payloads at different offsets
+	for chunk := 1; chunk <= len(stream); chunk++ {
+		assertLogLines(t, decodeInChunks(false, stream, chunk),
+			LogLine{Text: "oops", IsStderr: true},
+			LogLine{Text: "hello world"},
+			LogLine{Text: "second"},
+			LogLine{Text: "last", IsStderr: true})
+	}
+}
+
+func TestLogDecoderMatchesStdcopy(t *testing.T) {
+	var stream bytes.Buffer
+	stdout := stdcopy.NewStdWriter(&stream, stdcopy.Stdout)
+	stderr := stdcopy.NewStdWriter(&stream, stdcopy.Stderr)
+	stdout.Write([]byte("a\nb"))
+	stderr.Write([]byte("error: " + strings.Repeat("x", 300) + "\n"))
+	stdout.Write([]byte("c\n"))
+	assertLogLines(t, decodeInChunks(false, stream.Bytes(), 5),
+		LogLine{Text: "a"},
+		LogLine{Text: "error: " + strings.Repeat("x", 300), IsStderr: true},
+		LogLine{Text: "bc"})
+}
+
+func TestLogDecoderCutsEndlessLines(t *testing.T) {
+	stream := []byte(strings.Repeat("=", maxLogLineLen+10))
+	lines := decodeInChunks(true, stream, 5000)
+	if len(lines) != 2 || len(lines[0].Text) != maxLogLineLen || len(lines[1].Text) != 10 {
+		t.Errorf("expected the line to be cut at %d bytes, got %d lines", maxLogLineLen, len(lines))
+	}
+}
+
+func TestLogDecoderTimestamps(t *testing.T) {
+	collected := &collectedLines{}
+	decoder := NewLogDecoder(false, true, collected)
+	stream := append(frame(frameStdout, "2022-05-01T13:00:00.123456789Z started\n"), frame(frameStderr, "no timestamp\n")...)
+	decoder.Write(stream)
+	expected_time := time.Date(2022, 5, 1, 13, 0, 0, 123456789, time.UTC)
+	assertLogLines(t, collected.lines,
+		LogLine{Time: expected_time, Text: "started"},
+		LogLine{Text: "no timestamp", IsStderr: true})
+}
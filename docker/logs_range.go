@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogsRange limits the streamed logs to a time range, zero times leave that side open
+type LogsRange struct {
+	Since time.Time
+	Until time.Time
+}
+
+func (logs_range LogsRange) IsSet() bool {
+	return !logs_range.Since.IsZero() || !logs_range.Until.IsZero()
+}
+
+func (logs_range LogsRange) String() string {
+	since, until := "start", "now"
+	if !logs_range.Since.IsZero() {
+		since = logs_range.Since.Format(LogTimeLayout)
+	}
+	if !logs_range.Until.IsZero() {
+		until = logs_range.Until.Format(LogTimeLayout)
+	}
+	return fmt.Sprintf("%s - %s", since, until)
+}
+
+const LogTimeLayout = "2006-01-02 15:04:05"
+
+// the absolute times ParseLogTime accepts, in the local time zone unless they have one
+var logTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// the times of day ParseLogTime accepts, meaning today
+var timeOfDayLayouts = []string{"15:04:05", "15:04"}
+
+// ParseLogTime parses a time relative to now ("15m", "1h30m", "2d" ago) or an absolute time ("2022-05-01 13:00",
+// "13:00" today). An empty value is the zero time
+func ParseLogTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+		return now.AddDate(0, 0, -days), nil
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	for _, layout := range logTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return parsed, nil
+		}
+	}
+	for _, layout := range timeOfDayLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			year, month, day := now.Date()
+			return time.Date(year, month, day, parsed.Hour(), parsed.Minute(), parsed.Second(), 0, now.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("'%s' isn't a duration like 15m or a time like %s", value, LogTimeLayout)
+}
@@ -0,0 +1,28 @@
+package docker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLogTime(t *testing.T) {
+	now := time.Date(2022, 5, 1, 13, 30, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"":                 {},
+		"15m":              now.Add(-15 * time.Minute),
+		"1h30m":            now.Add(-90 * time.Minute),
+		"2d":               now.AddDate(0, 0, -2),
+		"2022-04-30 08:15": time.Date(2022, 4, 30, 8, 15, 0, 0, time.UTC),
+		"2022-04-30":       time.Date(2022, 4, 30, 0, 0, 0, 0, time.UTC),
+		"09:45":            time.Date(2022, 5, 1, 9, 45, 0, 0, time.UTC),
+	}
+	for value, expected := range cases {
+		parsed, err := ParseLogTime(value, now)
+		if err != nil || !parsed.Equal(expected) {
+			t.Errorf("%q: expected %s, got %s (%v)", value, expected, parsed, err)
+		}
+	}
+	if _, err := ParseLogTime("yesterday", now); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"context"
+	"dc-top/config"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// used by the engine when the container doesn't set its own StopTimeout
+const defaultStopTimeout = 10 * time.Second
+
+// StopTimeout resolves the stop timeout of a container from the config, falling back to the container's StopTimeout
+// and then to the configured default
+func StopTimeout(ctx context.Context, id string) time.Duration {
+	inspection, err := docker_cli.ContainerInspect(ctx, id)
+	if err != nil || inspection.Config == nil {
+		return configuredDefaultStopTimeout()
+	}
+	if timeout, ok := config.StopTimeout(strings.TrimPrefix(inspection.Name, "/"), inspection.Config.Labels); ok {
+		return timeout
+	}
+	if inspection.Config.StopTimeout != nil {
+		return time.Duration(*inspection.Config.StopTimeout) * time.Second
+	}
+	return configuredDefaultStopTimeout()
+}
+
+func configuredDefaultStopTimeout() time.Duration {
+	if timeout, ok := config.DefaultStopTimeout(); ok {
+		return timeout
+	}
+	return defaultStopTimeout
+}
+
+func StopSignal(ctx context.Context, id string) string {
+	inspection, err := docker_cli.ContainerInspect(ctx, id)
+	if err != nil || inspection.Config == nil || inspection.Config.StopSignal == "" {
+		return "SIGTERM"
+	}
+	return inspection.Config.StopSignal
+}
+
+// StopResult is how a container that was waited on stopped, Err is set when waiting failed
+type StopResult struct {
+	ExitCode int64
+	Err      error
+}
+
+// WaitUntilStopped returns a channel that gets the result once the container isn't running anymore or the wait failed
+func WaitUntilStopped(ctx context.Context, id string) <-chan StopResult {
+	stopped := make(chan StopResult, 1)
+	wait_ch, err_ch := docker_cli.ContainerWait(ctx, id, container.WaitConditionNotRunning)
+	go func() {
+		select {
+		case response := <-wait_ch:
+			result := StopResult{ExitCode: response.StatusCode}
+			if response.Error != nil {
+				result.Err = errors.New(response.Error.Message)
+			}
+			stopped <- result
+		case err := <-err_ch:
+			stopped <- StopResult{Err: err}
+		case <-ctx.Done():
+		}
+	}()
+	return stopped
+}
@@ -0,0 +1,179 @@
+package elements
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// CastHeader is the first line of an asciicast v2 file (https://docs.asciinema.org/manual/asciicast/v2/)
+type CastHeader struct {
+	Version       int               `json:"version"`
+	Width         int               `json:"width"`
+	Height        int               `json:"height"`
+	Timestamp     int64             `json:"timestamp,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	IdleTimeLimit float64           `json:"idle_time_limit,omitempty"`
+	Env           map[string]string `json:"env,omitempty"`
+}
+
+const (
+	CastOutput = "o"
+	CastInput  = "i"
+	CastResize = "r"
+	CastMarker = "m"
+)
+
+// CastEvent is a line of an asciicast file, `Time` is in seconds since the recording started
+type CastEvent struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// CastRecorder writes the output of a terminal session to an asciicast v2 file
+type CastRecorder struct {
+	lock  sync.Mutex
+	file  *os.File
+	start time.Time
+	// the end of a multi byte character that was split between reads, JSON strings must be valid UTF-8
+	pending []byte
+}
+
+func NewCastRecorder(path string, header CastHeader) (*CastRecorder, error) {
+	header.Version = 2
+	if header.Timestamp == 0 {
+		header.Timestamp = time.Now().Unix()
+	}
+	encoded_header, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = file.Write(append(encoded_header, '\n')); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &CastRecorder{file: file, start: time.Now()}, nil
+}
+
+func (r *CastRecorder) Output(p []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	data := append(r.pending, p...)
+	complete := completeUtf8(data)
+	r.pending = append([]byte(nil), data[complete:]...)
+	if complete > 0 {
+		r.writeEvent(CastOutput, string(data[:complete]))
+	}
+}
+
+func (r *CastRecorder) Resize(width, height int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.writeEvent(CastResize, fmt.Sprintf("%dx%d", width, height))
+}
+
+func (r *CastRecorder) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if len(r.pending) > 0 {
+		r.writeEvent(CastOutput, string(r.pending))
+		r.pending = nil
+	}
+	return r.file.Close()
+}
+
+func (r *CastRecorder) writeEvent(event_type, data string) {
+	elapsed := math.Round(time.Since(r.start).Seconds()*1e6) / 1e6
+	line, err := json.Marshal([]interface{}{elapsed, event_type, data})
+	if err != nil {
+		return
+	}
+	r.file.Write(append(line, '\n'))
+}
+
+// completeUtf8 returns the length of p without a trailing incomplete character
+func completeUtf8(p []byte) int {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if utf8.FullRune(p[i:]) {
+				return len(p)
+			}
+			return i
+		}
+	}
+	return len(p)
+}
+
+// ReadCast reads an asciicast v2 file, events are sorted by time as the format requires
+func ReadCast(path string) (CastHeader, []CastEvent, error) {
+	var header CastHeader
+	file, err := os.Open(path)
+	if err != nil {
+		return header, nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	if !scanner.Scan() {
+		if scanner.Err() != nil {
+			return header, nil, scanner.Err()
+		}
+		return header, nil, errors.New("empty recording")
+	}
+	if err = json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return header, nil, fmt.Errorf("bad header: %s", err)
+	}
+	if header.Version != 2 {
+		return header, nil, fmt.Errorf("unsupported asciicast version %d", header.Version)
+	}
+	if header.Width <= 0 || header.Height <= 0 {
+		return header, nil, fmt.Errorf("bad terminal size %dx%d", header.Width, header.Height)
+	}
+
+	var events []CastEvent
+	line_number := 1
+	for scanner.Scan() {
+		line_number++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var fields []interface{}
+		if err = json.Unmarshal(scanner.Bytes(), &fields); err != nil {
+			return header, nil, fmt.Errorf("bad event on line %d: %s", line_number, err)
+		}
+		if len(fields) != 3 {
+			return header, nil, fmt.Errorf("bad event on line %d: expected 3 fields", line_number)
+		}
+		event_time, time_ok := fields[0].(float64)
+		event_type, type_ok := fields[1].(string)
+		data, data_ok := fields[2].(string)
+		if !time_ok || !type_ok || !data_ok {
+			return header, nil, fmt.Errorf("bad event on line %d", line_number)
+		}
+		events = append(events, CastEvent{Time: event_time, Type: event_type, Data: data})
+	}
+	return header, events, scanner.Err()
+}
+
+// ParseCastSize parses the data of a resize event
+func ParseCastSize(data string) (width, height int, err error) {
+	if _, err = fmt.Sscanf(data, "%dx%d", &width, &height); err != nil {
+		return 0, 0, err
+	}
+	if width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("bad terminal size '%s'", data)
+	}
+	return width, height, nil
+}
@@ -0,0 +1,50 @@
+package elements
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCastRecordingRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.cast")
+	recorder, err := NewCastRecorder(path, CastHeader{Width: 80, Height: 24, Title: "api bash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "─" split between reads has to be written as one character
+	recorder.Output([]byte("$ ls\r\n\xe2\x94"))
+	recorder.Output([]byte("\x80\x1b[0m"))
+	recorder.Resize(100, 30)
+	recorder.Output([]byte("\xe6"))
+	if err = recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	header, events, err := ReadCast(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Version != 2 || header.Width != 80 || header.Height != 24 || header.Title != "api bash" || header.Timestamp == 0 {
+		t.Errorf("unexpected header %+v", header)
+	}
+	expected := []CastEvent{
+		{Type: CastOutput, Data: "$ ls\r\n"},
+		{Type: CastOutput, Data: "─\x1b[0m"},
+		{Type: CastResize, Data: "100x30"},
+		{Type: CastOutput, Data: "�"},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %+v", len(expected), events)
+	}
+	for i, event := range events {
+		if event.Type != expected[i].Type || event.Data != expected[i].Data {
+			t.Errorf("event %d: expected %+v, got %+v", i, expected[i], event)
+		}
+		if i > 0 && event.Time < events[i-1].Time {
+			t.Errorf("event %d is earlier than the previous one", i)
+		}
+	}
+	if width, height, err := ParseCastSize(events[2].Data); err != nil || width != 100 || height != 30 {
+		t.Errorf("unexpected size %dx%d (%v)", width, height, err)
+	}
+}
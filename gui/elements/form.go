@@ -0,0 +1,102 @@
+package elements
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+type Form struct {
+	labels      []string
+	boxes       []TextBox
+	focused     int
+	label_width int
+}
+
+func NewForm(labels []string, values []string, default_style tcell.Style, cursor_style tcell.Style) Form {
+	label_width := 0
+	for _, label := range labels {
+		if len(label) > label_width {
+			label_width = len(label)
+		}
+	}
+	label_width += 2
+
+	boxes := make([]TextBox, len(labels))
+	for i := range labels {
+		boxes[i] = NewTextBox(EmptyDrawer(), 0, default_style, cursor_style, i == 0)
+		if i < len(values) {
+			boxes[i].SetText(values[i])
+			boxes[i].End()
+		}
+	}
+	return Form{
+		labels:      labels,
+		boxes:       boxes,
+		focused:     0,
+		label_width: label_width,
+	}
+}
+
+func (form *Form) Len() int {
+	return len(form.boxes)
+}
+
+func (form *Form) Focused() int {
+	return form.focused
+}
+
+func (form *Form) Focus(index int) {
+	if index < 0 || index >= len(form.boxes) {
+		return
+	}
+	form.boxes[form.focused].Unfocus()
+	form.focused = index
+	form.boxes[form.focused].Focus()
+}
+
+func (form *Form) Next() {
+	form.Focus((form.focused + 1) % len(form.boxes))
+}
+
+func (form *Form) Prev() {
+	form.Focus((form.focused - 1 + len(form.boxes)) % len(form.boxes))
+}
+
+func (form *Form) Value(index int) string {
+	return form.boxes[index].Value()
+}
+
+func (form *Form) SetValue(index int, value string) {
+	form.boxes[index].SetText(value)
+	form.boxes[index].End()
+}
+
+func (form *Form) Values() []string {
+	values := make([]string, len(form.boxes))
+	for i := range form.boxes {
+		values[i] = form.boxes[i].Value()
+	}
+	return values
+}
+
+func (form *Form) HandleKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyUp, tcell.KeyBacktab:
+		form.Prev()
+	case tcell.KeyDown, tcell.KeyTab:
+		form.Next()
+	default:
+		form.boxes[form.focused].HandleKey(ev)
+	}
+}
+
+func (form *Form) Style() []StringStyler {
+	rows := make([]StringStyler, len(form.boxes))
+	for i, label := range form.labels {
+		label_style := tcell.StyleDefault
+		if i == form.focused {
+			label_style = label_style.Bold(true).Foreground(tcell.ColorYellow)
+		}
+		rows[i] = TextDrawer(label+":", label_style).Concat(form.label_width, form.boxes[i].Style())
+	}
+	return rows
+}
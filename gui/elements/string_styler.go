@@ -176,3 +176,22 @@ func ValuesBarDrawer(description string, min_val float64, max_val float64, curr_
 	normalized_curr := curr_val - min_val
 	return PercentageBarDrawer(description, 100.0*normalized_curr/normalized_max, bar_len, extra_info)
 }
+
+// MatchesHighlightDrawer highlights the [start, end) byte ranges of str, like the ones regexp finds
+func MatchesHighlightDrawer(str string, matches [][]int, default_style tcell.Style) StringStyler {
+	highlighted_indices := make([]bool, len(str))
+	for _, match := range matches {
+		for j := match[0]; j < match[1] && j < len(str); j++ {
+			highlighted_indices[j] = true
+		}
+	}
+	return func(i int) (rune, tcell.Style) {
+		if i < len(str) {
+			if highlighted_indices[i] {
+				return rune(str[i]), default_style.Reverse(true)
+			}
+			return rune(str[i]), default_style
+		}
+		return '\x00', tcell.StyleDefault
+	}
+}
@@ -0,0 +1,625 @@
+package elements
+
+import (
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+const DefaultScrollback = 2000
+
+type terminalCell struct {
+	r     rune
+	style tcell.Style
+	// the right half of a double width rune
+	is_continuation bool
+}
+
+type savedCursor struct {
+	x, y           int
+	style          tcell.Style
+	origin_mode    bool
+	charsets       [2]byte
+	active_charset int
+}
+
+// Terminal is a VT100/xterm emulator, output of a TTY is written into it and drawn from snapshots
+type Terminal struct {
+	lock sync.Mutex
+
+	width  int
+	height int
+
+	primary        [][]terminalCell
+	alternate      [][]terminalCell
+	is_alternate   bool
+	scrollback     [][]terminalCell
+	max_scrollback int
+	scroll_offset  int
+
+	cursor_x     int
+	cursor_y     int
+	wrap_pending bool
+	style        tcell.Style
+	saved        savedCursor
+	alt_saved    savedCursor
+	scroll_top   int
+	scroll_bot   int
+	tab_stops    []bool
+	last_printed rune
+
+	auto_wrap       bool
+	origin_mode     bool
+	insert_mode     bool
+	newline_mode    bool
+	cursor_visible  bool
+	app_cursor_keys bool
+	app_keypad      bool
+	bracketed_paste bool
+
+	charsets       [2]byte
+	active_charset int
+
+	parser    terminalParser
+	title     string
+	responder func([]byte)
+}
+
+func NewTerminal(width, height int) *Terminal {
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	t := &Terminal{
+		width:          width,
+		height:         height,
+		max_scrollback: DefaultScrollback,
+	}
+	t.reset()
+	return t
+}
+
+// SetResponder sets where replies to terminal queries (e.g. cursor position reports) are written
+func (t *Terminal) SetResponder(responder func([]byte)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.responder = responder
+}
+
+func (t *Terminal) Write(p []byte) (int, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, b := range p {
+		t.feed(b)
+	}
+	return len(p), nil
+}
+
+func (t *Terminal) Size() (int, int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.width, t.height
+}
+
+func (t *Terminal) Title() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.title
+}
+
+func (t *Terminal) Resize(width, height int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	if width == t.width && height == t.height {
+		return
+	}
+
+	// lines above the cursor that don't fit anymore are pushed to the scrollback, so the cursor stays on screen
+	if excess := t.cursor_y - height + 1; excess > 0 {
+		grid := t.grid()
+		if !t.is_alternate {
+			for _, line := range t.primary[:excess] {
+				t.pushScrollback(line)
+			}
+			t.saved.y -= excess
+		}
+		*grid = (*grid)[excess:]
+		t.cursor_y -= excess
+	}
+	t.primary = resizeGrid(t.primary, width, height)
+	t.alternate = resizeGrid(t.alternate, width, height)
+
+	old_tab_stops := t.tab_stops
+	t.tab_stops = make([]bool, width)
+	for x := range t.tab_stops {
+		if x < len(old_tab_stops) {
+			t.tab_stops[x] = old_tab_stops[x]
+		} else {
+			t.tab_stops[x] = x%8 == 0
+		}
+	}
+
+	t.width, t.height = width, height
+	t.scroll_top, t.scroll_bot = 0, height-1
+	t.cursor_x = clamp(t.cursor_x, 0, width-1)
+	t.cursor_y = clamp(t.cursor_y, 0, height-1)
+	t.saved.x = clamp(t.saved.x, 0, width-1)
+	t.saved.y = clamp(t.saved.y, 0, height-1)
+	t.wrap_pending = false
+}
+
+// ScrollBack moves the view `lines` lines into the scrollback, negative values move it back towards the screen
+func (t *Terminal) ScrollBack(lines int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.is_alternate {
+		return
+	}
+	t.scroll_offset = clamp(t.scroll_offset+lines, 0, len(t.scrollback))
+}
+
+func (t *Terminal) ResetScroll() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.scroll_offset = 0
+}
+
+func (t *Terminal) IsScrolled() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.scroll_offset > 0
+}
+
+// TerminalSnapshot is a copy of the visible part of a terminal
+type TerminalSnapshot struct {
+	cells         [][]terminalCell
+	CursorX       int
+	CursorY       int
+	CursorVisible bool
+}
+
+func (t *Terminal) Snapshot() TerminalSnapshot {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	cells := make([][]terminalCell, t.height)
+	for y := range cells {
+		var line []terminalCell
+		if t.is_alternate {
+			line = t.alternate[y]
+		} else if index := len(t.scrollback) - t.scroll_offset + y; index < len(t.scrollback) {
+			line = t.scrollback[index]
+		} else {
+			line = t.primary[index-len(t.scrollback)]
+		}
+		cells[y] = make([]terminalCell, len(line))
+		copy(cells[y], line)
+	}
+	return TerminalSnapshot{
+		cells:         cells,
+		CursorX:       t.cursor_x,
+		CursorY:       t.cursor_y,
+		CursorVisible: t.cursor_visible && t.scroll_offset == 0,
+	}
+}
+
+// Cell can be used as a contents generator for drawing the snapshot
+func (s *TerminalSnapshot) Cell(x, y int) (rune, tcell.Style) {
+	if y >= len(s.cells) || x >= len(s.cells[y]) {
+		return ' ', tcell.StyleDefault
+	}
+	cell := s.cells[y][x]
+	if cell.is_continuation || cell.r == 0 {
+		return ' ', cell.style
+	}
+	return cell.r, cell.style
+}
+
+// Line returns the text of a row without trailing spaces
+func (s *TerminalSnapshot) Line(y int) string {
+	if y >= len(s.cells) {
+		return ""
+	}
+	runes := make([]rune, 0, len(s.cells[y]))
+	for _, cell := range s.cells[y] {
+		if !cell.is_continuation {
+			runes = append(runes, cell.r)
+		}
+	}
+	end := len(runes)
+	for end > 0 && runes[end-1] == ' ' {
+		end--
+	}
+	return string(runes[:end])
+}
+
+func (t *Terminal) reset() {
+	t.primary = newGrid(t.width, t.height)
+	t.alternate = newGrid(t.width, t.height)
+	t.is_alternate = false
+	t.scrollback = nil
+	t.scroll_offset = 0
+	t.cursor_x, t.cursor_y = 0, 0
+	t.wrap_pending = false
+	t.style = tcell.StyleDefault
+	t.scroll_top, t.scroll_bot = 0, t.height-1
+	t.tab_stops = make([]bool, t.width)
+	for x := range t.tab_stops {
+		t.tab_stops[x] = x%8 == 0
+	}
+	t.auto_wrap = true
+	t.origin_mode = false
+	t.insert_mode = false
+	t.newline_mode = false
+	t.cursor_visible = true
+	t.app_cursor_keys = false
+	t.app_keypad = false
+	t.bracketed_paste = false
+	t.charsets = [2]byte{'B', 'B'}
+	t.active_charset = 0
+	t.saved = t.currentCursor()
+	t.alt_saved = t.saved
+	t.parser = terminalParser{}
+}
+
+func (t *Terminal) grid() *[][]terminalCell {
+	if t.is_alternate {
+		return &t.alternate
+	}
+	return &t.primary
+}
+
+func (t *Terminal) blank() terminalCell {
+	_, bg, _ := t.style.Decompose()
+	return terminalCell{r: ' ', style: tcell.StyleDefault.Background(bg)}
+}
+
+func (t *Terminal) blankLine() []terminalCell {
+	line := make([]terminalCell, t.width)
+	blank := t.blank()
+	for x := range line {
+		line[x] = blank
+	}
+	return line
+}
+
+func (t *Terminal) pushScrollback(line []terminalCell) {
+	t.scrollback = append(t.scrollback, line)
+	if len(t.scrollback) > t.max_scrollback {
+		t.scrollback = t.scrollback[len(t.scrollback)-t.max_scrollback:]
+	} else if t.scroll_offset > 0 {
+		// keep the viewed lines in place while output arrives
+		t.scroll_offset++
+	}
+}
+
+func (t *Terminal) print(r rune) {
+	if t.charsets[t.active_charset] == '0' {
+		if mapped, ok := lineDrawingCharset[r]; ok {
+			r = mapped
+		}
+	}
+	rune_width := runewidth.RuneWidth(r)
+	if rune_width == 0 {
+		// combining characters aren't supported
+		return
+	}
+	if rune_width > t.width {
+		return
+	}
+	if t.wrap_pending && t.auto_wrap {
+		t.cursor_x = 0
+		t.lineFeed()
+	}
+	t.wrap_pending = false
+	if t.cursor_x+rune_width > t.width {
+		if !t.auto_wrap {
+			t.cursor_x = t.width - rune_width
+		} else {
+			t.setCell(t.cursor_x, t.cursor_y, t.blank())
+			t.cursor_x = 0
+			t.lineFeed()
+		}
+	}
+	if t.insert_mode {
+		t.insertCells(rune_width)
+	}
+	t.setCell(t.cursor_x, t.cursor_y, terminalCell{r: r, style: t.style})
+	if rune_width == 2 {
+		t.setCell(t.cursor_x+1, t.cursor_y, terminalCell{r: ' ', style: t.style, is_continuation: true})
+	}
+	t.last_printed = r
+	t.cursor_x += rune_width
+	if t.cursor_x >= t.width {
+		t.cursor_x = t.width - 1
+		t.wrap_pending = true
+	}
+}
+
+// setCell also clears the other half of any double width rune that is overwritten
+func (t *Terminal) setCell(x, y int, cell terminalCell) {
+	line := (*t.grid())[y]
+	if line[x].is_continuation && x > 0 {
+		line[x-1] = t.blank()
+	}
+	if x+1 < len(line) && line[x+1].is_continuation && !cell.is_continuation {
+		line[x+1] = t.blank()
+	}
+	line[x] = cell
+}
+
+func (t *Terminal) lineFeed() {
+	if t.cursor_y == t.scroll_bot {
+		t.scrollUp(1)
+	} else if t.cursor_y < t.height-1 {
+		t.cursor_y++
+	}
+}
+
+func (t *Terminal) reverseIndex() {
+	if t.cursor_y == t.scroll_top {
+		t.scrollDown(1)
+	} else if t.cursor_y > 0 {
+		t.cursor_y--
+	}
+}
+
+// scrollUp moves the lines of the scroll region up, lines leaving the top of the screen are kept in the scrollback
+func (t *Terminal) scrollUp(n int) {
+	t.scrollRegionUp(n, t.scroll_top == 0 && !t.is_alternate)
+}
+
+func (t *Terminal) scrollRegionUp(n int, keep_scrollback bool) {
+	grid := *t.grid()
+	n = clamp(n, 0, t.scroll_bot-t.scroll_top+1)
+	for i := 0; i < n; i++ {
+		if keep_scrollback {
+			t.pushScrollback(grid[t.scroll_top])
+		}
+		copy(grid[t.scroll_top:t.scroll_bot], grid[t.scroll_top+1:t.scroll_bot+1])
+		grid[t.scroll_bot] = t.blankLine()
+	}
+}
+
+func (t *Terminal) scrollDown(n int) {
+	grid := *t.grid()
+	n = clamp(n, 0, t.scroll_bot-t.scroll_top+1)
+	for i := 0; i < n; i++ {
+		copy(grid[t.scroll_top+1:t.scroll_bot+1], grid[t.scroll_top:t.scroll_bot])
+		grid[t.scroll_top] = t.blankLine()
+	}
+}
+
+func (t *Terminal) insertLines(n int) {
+	if t.cursor_y < t.scroll_top || t.cursor_y > t.scroll_bot {
+		return
+	}
+	top := t.scroll_top
+	t.scroll_top = t.cursor_y
+	t.scrollDown(n)
+	t.scroll_top = top
+	t.cursor_x = 0
+	t.wrap_pending = false
+}
+
+func (t *Terminal) deleteLines(n int) {
+	if t.cursor_y < t.scroll_top || t.cursor_y > t.scroll_bot {
+		return
+	}
+	top := t.scroll_top
+	t.scroll_top = t.cursor_y
+	// deleted lines aren't part of the scrollback
+	t.scrollRegionUp(n, false)
+	t.scroll_top = top
+	t.cursor_x = 0
+	t.wrap_pending = false
+}
+
+func (t *Terminal) insertCells(n int) {
+	line := (*t.grid())[t.cursor_y]
+	n = clamp(n, 0, t.width-t.cursor_x)
+	if line[t.cursor_x].is_continuation {
+		t.setCell(t.cursor_x, t.cursor_y, t.blank())
+	}
+	copy(line[t.cursor_x+n:], line[t.cursor_x:])
+	for x := t.cursor_x; x < t.cursor_x+n; x++ {
+		line[x] = t.blank()
+	}
+	if !line[t.width-1].is_continuation && runewidth.RuneWidth(line[t.width-1].r) == 2 {
+		line[t.width-1] = t.blank()
+	}
+}
+
+func (t *Terminal) deleteCells(n int) {
+	line := (*t.grid())[t.cursor_y]
+	n = clamp(n, 0, t.width-t.cursor_x)
+	copy(line[t.cursor_x:], line[t.cursor_x+n:])
+	for x := t.width - n; x < t.width; x++ {
+		line[x] = t.blank()
+	}
+	if line[t.cursor_x].is_continuation {
+		line[t.cursor_x] = t.blank()
+	}
+}
+
+func (t *Terminal) eraseCells(y, from, to int) {
+	from = clamp(from, 0, t.width)
+	to = clamp(to, 0, t.width)
+	for x := from; x < to; x++ {
+		t.setCell(x, y, t.blank())
+	}
+}
+
+func (t *Terminal) eraseInDisplay(mode int) {
+	switch mode {
+	case 0:
+		t.eraseCells(t.cursor_y, t.cursor_x, t.width)
+		for y := t.cursor_y + 1; y < t.height; y++ {
+			(*t.grid())[y] = t.blankLine()
+		}
+	case 1:
+		for y := 0; y < t.cursor_y; y++ {
+			(*t.grid())[y] = t.blankLine()
+		}
+		t.eraseCells(t.cursor_y, 0, t.cursor_x+1)
+	case 2:
+		for y := 0; y < t.height; y++ {
+			(*t.grid())[y] = t.blankLine()
+		}
+	case 3:
+		t.scrollback = nil
+		t.scroll_offset = 0
+	}
+}
+
+func (t *Terminal) eraseInLine(mode int) {
+	switch mode {
+	case 0:
+		t.eraseCells(t.cursor_y, t.cursor_x, t.width)
+	case 1:
+		t.eraseCells(t.cursor_y, 0, t.cursor_x+1)
+	case 2:
+		t.eraseCells(t.cursor_y, 0, t.width)
+	}
+}
+
+// moveCursor places the cursor, rows are relative to the scroll region when origin mode is set
+func (t *Terminal) moveCursor(x, y int) {
+	min_y, max_y := 0, t.height-1
+	if t.origin_mode {
+		y += t.scroll_top
+		min_y, max_y = t.scroll_top, t.scroll_bot
+	}
+	t.cursor_x = clamp(x, 0, t.width-1)
+	t.cursor_y = clamp(y, min_y, max_y)
+	t.wrap_pending = false
+}
+
+// moveCursorRelative doesn't leave the scroll region if the cursor started inside it
+func (t *Terminal) moveCursorRelative(dx, dy int) {
+	min_y, max_y := 0, t.height-1
+	if t.cursor_y >= t.scroll_top && t.cursor_y <= t.scroll_bot {
+		min_y, max_y = t.scroll_top, t.scroll_bot
+	}
+	t.cursor_x = clamp(t.cursor_x+dx, 0, t.width-1)
+	t.cursor_y = clamp(t.cursor_y+dy, min_y, max_y)
+	t.wrap_pending = false
+}
+
+func (t *Terminal) tab(n int) {
+	for ; n > 0 && t.cursor_x < t.width-1; n-- {
+		t.cursor_x++
+		for t.cursor_x < t.width-1 && !t.tab_stops[t.cursor_x] {
+			t.cursor_x++
+		}
+	}
+	t.wrap_pending = false
+}
+
+func (t *Terminal) backTab(n int) {
+	for ; n > 0 && t.cursor_x > 0; n-- {
+		t.cursor_x--
+		for t.cursor_x > 0 && !t.tab_stops[t.cursor_x] {
+			t.cursor_x--
+		}
+	}
+	t.wrap_pending = false
+}
+
+func (t *Terminal) currentCursor() savedCursor {
+	return savedCursor{
+		x:              t.cursor_x,
+		y:              t.cursor_y,
+		style:          t.style,
+		origin_mode:    t.origin_mode,
+		charsets:       t.charsets,
+		active_charset: t.active_charset,
+	}
+}
+
+func (t *Terminal) saveCursor() {
+	if t.is_alternate {
+		t.alt_saved = t.currentCursor()
+	} else {
+		t.saved = t.currentCursor()
+	}
+}
+
+func (t *Terminal) restoreCursor() {
+	saved := t.saved
+	if t.is_alternate {
+		saved = t.alt_saved
+	}
+	t.cursor_x = clamp(saved.x, 0, t.width-1)
+	t.cursor_y = clamp(saved.y, 0, t.height-1)
+	t.style = saved.style
+	t.origin_mode = saved.origin_mode
+	t.charsets = saved.charsets
+	t.active_charset = saved.active_charset
+	t.wrap_pending = false
+}
+
+func (t *Terminal) switchScreen(alternate bool, clear bool) {
+	if t.is_alternate == alternate {
+		return
+	}
+	t.is_alternate = alternate
+	t.scroll_offset = 0
+	if alternate && clear {
+		t.alternate = newGrid(t.width, t.height)
+	}
+}
+
+func (t *Terminal) respond(response string) {
+	if t.responder != nil {
+		t.responder([]byte(response))
+	}
+}
+
+func newGrid(width, height int) [][]terminalCell {
+	grid := make([][]terminalCell, height)
+	for y := range grid {
+		grid[y] = make([]terminalCell, width)
+		for x := range grid[y] {
+			grid[y][x] = terminalCell{r: ' ', style: tcell.StyleDefault}
+		}
+	}
+	return grid
+}
+
+func resizeGrid(grid [][]terminalCell, width, height int) [][]terminalCell {
+	resized := newGrid(width, height)
+	for y := 0; y < height && y < len(grid); y++ {
+		copy(resized[y], grid[y])
+		if width < len(grid[y]) && resized[y][width-1].r != ' ' && runewidth.RuneWidth(resized[y][width-1].r) == 2 {
+			resized[y][width-1] = terminalCell{r: ' ', style: tcell.StyleDefault}
+		}
+	}
+	return resized
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+// DEC special graphics, used by ncurses for borders
+var lineDrawingCharset = map[rune]rune{
+	'`': '◆', 'a': '▒', 'b': '␉', 'c': '␌', 'd': '␍', 'e': '␊', 'f': '°', 'g': '±',
+	'h': '␤', 'i': '␋', 'j': '┘', 'k': '┐', 'l': '┌', 'm': '└', 'n': '┼', 'o': '⎺',
+	'p': '⎻', 'q': '─', 'r': '⎼', 's': '⎽', 't': '├', 'u': '┤', 'v': '┴', 'w': '┬',
+	'x': '│', 'y': '≤', 'z': '≥', '{': 'π', '|': '≠', '}': '£', '~': '·',
+}
@@ -0,0 +1,117 @@
+package elements
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// keys that are sent as "ESC [ <code> ~"
+var tildeKeyCodes = map[tcell.Key]int{
+	tcell.KeyInsert: 2,
+	tcell.KeyDelete: 3,
+	tcell.KeyPgUp:   5,
+	tcell.KeyPgDn:   6,
+	tcell.KeyF5:     15,
+	tcell.KeyF6:     17,
+	tcell.KeyF7:     18,
+	tcell.KeyF8:     19,
+	tcell.KeyF9:     20,
+	tcell.KeyF10:    21,
+	tcell.KeyF11:    23,
+	tcell.KeyF12:    24,
+}
+
+// keys that are sent as "ESC [ <final>", or "ESC O <final>" in application cursor mode
+var cursorKeyFinals = map[tcell.Key]byte{
+	tcell.KeyUp:    'A',
+	tcell.KeyDown:  'B',
+	tcell.KeyRight: 'C',
+	tcell.KeyLeft:  'D',
+	tcell.KeyHome:  'H',
+	tcell.KeyEnd:   'F',
+}
+
+var functionKeyFinals = map[tcell.Key]byte{
+	tcell.KeyF1: 'P',
+	tcell.KeyF2: 'Q',
+	tcell.KeyF3: 'R',
+	tcell.KeyF4: 'S',
+}
+
+// EncodeKey returns the bytes an xterm would send for the key, according to the current terminal modes
+func (t *Terminal) EncodeKey(ev *tcell.EventKey) []byte {
+	t.lock.Lock()
+	app_cursor_keys := t.app_cursor_keys
+	newline_mode := t.newline_mode
+	t.lock.Unlock()
+
+	modifiers := ev.Modifiers()
+	// xterm's modifier parameter: 1 + shift + 2*alt + 4*ctrl
+	modifier_param := 1
+	if modifiers&tcell.ModShift != 0 {
+		modifier_param += 1
+	}
+	if modifiers&tcell.ModAlt != 0 {
+		modifier_param += 2
+	}
+	if modifiers&tcell.ModCtrl != 0 {
+		modifier_param += 4
+	}
+	alt_prefix := func(b []byte) []byte {
+		if modifiers&tcell.ModAlt != 0 {
+			return append([]byte{0x1b}, b...)
+		}
+		return b
+	}
+
+	key := ev.Key()
+	if final, ok := cursorKeyFinals[key]; ok {
+		if modifier_param > 1 {
+			return []byte(fmt.Sprintf("\x1b[1;%d%c", modifier_param, final))
+		}
+		if app_cursor_keys {
+			return []byte{0x1b, 'O', final}
+		}
+		return []byte{0x1b, '[', final}
+	}
+	if final, ok := functionKeyFinals[key]; ok {
+		if modifier_param > 1 {
+			return []byte(fmt.Sprintf("\x1b[1;%d%c", modifier_param, final))
+		}
+		return []byte{0x1b, 'O', final}
+	}
+	if code, ok := tildeKeyCodes[key]; ok {
+		if modifier_param > 1 {
+			return []byte(fmt.Sprintf("\x1b[%d;%d~", code, modifier_param))
+		}
+		return []byte(fmt.Sprintf("\x1b[%d~", code))
+	}
+
+	switch key {
+	case tcell.KeyRune:
+		return alt_prefix([]byte(string(ev.Rune())))
+	case tcell.KeyBacktab:
+		return []byte("\x1b[Z")
+	case tcell.KeyEnter:
+		if newline_mode {
+			return alt_prefix([]byte("\r\n"))
+		}
+		return alt_prefix([]byte("\r"))
+	}
+	// the remaining keys are control characters (Ctrl+A is 0x01, Backspace is 0x7f...)
+	if key < 0x20 || key == tcell.KeyDEL {
+		return alt_prefix([]byte{byte(key)})
+	}
+	return nil
+}
+
+// EncodePaste wraps pasted text with the bracketed paste markers if the application asked for them
+func (t *Terminal) EncodePaste(text string) []byte {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.bracketed_paste {
+		return []byte("\x1b[200~" + text + "\x1b[201~")
+	}
+	return []byte(text)
+}
@@ -0,0 +1,523 @@
+package elements
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type parserState uint8
+
+const (
+	groundState parserState = iota
+	escapeState
+	escapeIntermediateState
+	csiState
+	oscState
+	ignoredStringState
+	stringEscapeState
+)
+
+const maxSequenceLength = 4096
+
+type terminalParser struct {
+	state parserState
+	// the state to return to after ESC inside a string, which may be the start of a string terminator
+	string_state parserState
+	utf8_buffer  []byte
+	intermediate []byte
+	sequence     []byte
+}
+
+func (t *Terminal) feed(b byte) {
+	p := &t.parser
+
+	switch p.state {
+	case oscState, ignoredStringState:
+		switch b {
+		case 0x07:
+			t.endString()
+		case 0x1b:
+			p.string_state = p.state
+			p.state = stringEscapeState
+		case 0x18, 0x1a:
+			p.state = groundState
+		default:
+			if p.state == oscState && len(p.sequence) < maxSequenceLength {
+				p.sequence = append(p.sequence, b)
+			}
+		}
+		return
+	case stringEscapeState:
+		if b == '\\' {
+			p.state = p.string_state
+			t.endString()
+			return
+		}
+		// any other escape aborts the string and starts a new sequence
+		p.state = escapeState
+		p.intermediate = p.intermediate[:0]
+		t.feed(b)
+		return
+	}
+
+	if b < 0x20 || b == 0x7f {
+		t.control(b)
+		return
+	}
+
+	switch p.state {
+	case groundState:
+		t.feedPrintable(b)
+	case escapeState:
+		switch {
+		case b == '[':
+			p.state = csiState
+			p.sequence = p.sequence[:0]
+			p.intermediate = p.intermediate[:0]
+		case b == ']':
+			p.state = oscState
+			p.sequence = p.sequence[:0]
+		case b == 'P' || b == 'X' || b == '^' || b == '_':
+			p.state = ignoredStringState
+		case b >= 0x20 && b <= 0x2f:
+			p.intermediate = append(p.intermediate[:0], b)
+			p.state = escapeIntermediateState
+		default:
+			p.state = groundState
+			t.escape(b)
+		}
+	case escapeIntermediateState:
+		if b >= 0x20 && b <= 0x2f {
+			p.intermediate = append(p.intermediate, b)
+			return
+		}
+		p.state = groundState
+		t.escapeWithIntermediate(p.intermediate[0], b)
+	case csiState:
+		switch {
+		case b >= 0x40 && b <= 0x7e:
+			p.state = groundState
+			if len(p.sequence) < maxSequenceLength {
+				t.csi(b)
+			}
+		case b >= 0x20 && b <= 0x2f:
+			p.intermediate = append(p.intermediate, b)
+		default:
+			if len(p.sequence) < maxSequenceLength {
+				p.sequence = append(p.sequence, b)
+			}
+		}
+	}
+}
+
+func (t *Terminal) feedPrintable(b byte) {
+	p := &t.parser
+	if b < utf8.RuneSelf && len(p.utf8_buffer) == 0 {
+		t.print(rune(b))
+		return
+	}
+	p.utf8_buffer = append(p.utf8_buffer, b)
+	if !utf8.FullRune(p.utf8_buffer) {
+		return
+	}
+	r, size := utf8.DecodeRune(p.utf8_buffer)
+	t.print(r)
+	rest := p.utf8_buffer[size:]
+	p.utf8_buffer = p.utf8_buffer[:0]
+	for _, b := range rest {
+		t.feedPrintable(b)
+	}
+}
+
+func (t *Terminal) control(b byte) {
+	switch b {
+	case 0x07: // BEL
+	case 0x08: // BS
+		if t.cursor_x > 0 {
+			t.cursor_x--
+		}
+		t.wrap_pending = false
+	case 0x09: // HT
+		t.tab(1)
+	case 0x0a, 0x0b, 0x0c: // LF, VT, FF
+		t.lineFeed()
+		if t.newline_mode {
+			t.cursor_x = 0
+		}
+		t.wrap_pending = false
+	case 0x0d: // CR
+		t.cursor_x = 0
+		t.wrap_pending = false
+	case 0x0e: // SO
+		t.active_charset = 1
+	case 0x0f: // SI
+		t.active_charset = 0
+	case 0x18, 0x1a: // CAN, SUB
+		t.parser.state = groundState
+	case 0x1b: // ESC
+		t.parser.state = escapeState
+		t.parser.intermediate = t.parser.intermediate[:0]
+	}
+}
+
+func (t *Terminal) escape(final byte) {
+	switch final {
+	case '7': // DECSC
+		t.saveCursor()
+	case '8': // DECRC
+		t.restoreCursor()
+	case 'D': // IND
+		t.lineFeed()
+		t.wrap_pending = false
+	case 'E': // NEL
+		t.cursor_x = 0
+		t.lineFeed()
+		t.wrap_pending = false
+	case 'H': // HTS
+		t.tab_stops[t.cursor_x] = true
+	case 'M': // RI
+		t.reverseIndex()
+		t.wrap_pending = false
+	case 'c': // RIS
+		t.reset()
+	case '=': // DECKPAM
+		t.app_keypad = true
+	case '>': // DECKPNM
+		t.app_keypad = false
+	}
+}
+
+func (t *Terminal) escapeWithIntermediate(intermediate byte, final byte) {
+	switch intermediate {
+	case '(':
+		t.charsets[0] = final
+	case ')':
+		t.charsets[1] = final
+	case '#':
+		if final == '8' { // DECALN
+			for y := 0; y < t.height; y++ {
+				for x := 0; x < t.width; x++ {
+					(*t.grid())[y][x] = terminalCell{r: 'E', style: tcell.StyleDefault}
+				}
+			}
+		}
+	}
+}
+
+func (t *Terminal) endString() {
+	p := &t.parser
+	if p.state == oscState {
+		t.osc(string(p.sequence))
+	}
+	p.state = groundState
+}
+
+func (t *Terminal) osc(command string) {
+	parts := strings.SplitN(command, ";", 2)
+	if len(parts) == 2 && (parts[0] == "0" || parts[0] == "2") {
+		t.title = parts[1]
+	}
+}
+
+// csiParams are the ';' separated parameters of a control sequence, each may have ':' separated sub parameters
+type csiParams [][]int
+
+func parseCsiParams(raw string) csiParams {
+	if raw == "" {
+		return nil
+	}
+	groups := strings.Split(raw, ";")
+	params := make(csiParams, len(groups))
+	for i, group := range groups {
+		sub_params := strings.Split(group, ":")
+		params[i] = make([]int, len(sub_params))
+		for j, sub_param := range sub_params {
+			value, err := strconv.Atoi(sub_param)
+			if err != nil || value < 0 {
+				value = 0
+			}
+			params[i][j] = value
+		}
+	}
+	return params
+}
+
+// get returns the i'th parameter, missing and zero parameters are replaced by the default
+func (params csiParams) get(i int, default_value int) int {
+	if i >= len(params) || len(params[i]) == 0 || params[i][0] == 0 {
+		return default_value
+	}
+	return params[i][0]
+}
+
+func (t *Terminal) csi(final byte) {
+	raw := string(t.parser.sequence)
+	var private byte
+	if len(raw) > 0 && (raw[0] == '?' || raw[0] == '>' || raw[0] == '<' || raw[0] == '=') {
+		private = raw[0]
+		raw = raw[1:]
+	}
+	params := parseCsiParams(raw)
+	if len(t.parser.intermediate) > 0 {
+		// e.g. DECSCUSR (cursor shape), which can't be changed inside the window
+		return
+	}
+
+	switch private {
+	case '?':
+		switch final {
+		case 'h':
+			t.setPrivateModes(params, true)
+		case 'l':
+			t.setPrivateModes(params, false)
+		}
+		return
+	case '>':
+		if final == 'c' {
+			t.respond("\x1b[>0;10;0c")
+		}
+		return
+	case 0:
+	default:
+		return
+	}
+
+	switch final {
+	case '@': // ICH
+		t.insertCells(params.get(0, 1))
+	case 'A': // CUU
+		t.moveCursorRelative(0, -params.get(0, 1))
+	case 'B', 'e': // CUD, VPR
+		t.moveCursorRelative(0, params.get(0, 1))
+	case 'C', 'a': // CUF, HPR
+		t.moveCursorRelative(params.get(0, 1), 0)
+	case 'D': // CUB
+		t.moveCursorRelative(-params.get(0, 1), 0)
+	case 'E': // CNL
+		t.moveCursorRelative(0, params.get(0, 1))
+		t.cursor_x = 0
+	case 'F': // CPL
+		t.moveCursorRelative(0, -params.get(0, 1))
+		t.cursor_x = 0
+	case 'G', '`': // CHA, HPA
+		t.cursor_x = clamp(params.get(0, 1)-1, 0, t.width-1)
+		t.wrap_pending = false
+	case 'H', 'f': // CUP, HVP
+		t.moveCursor(params.get(1, 1)-1, params.get(0, 1)-1)
+	case 'I': // CHT
+		t.tab(params.get(0, 1))
+	case 'J': // ED
+		t.eraseInDisplay(params.get(0, 0))
+	case 'K': // EL
+		t.eraseInLine(params.get(0, 0))
+	case 'L': // IL
+		t.insertLines(params.get(0, 1))
+	case 'M': // DL
+		t.deleteLines(params.get(0, 1))
+	case 'P': // DCH
+		t.deleteCells(params.get(0, 1))
+	case 'S': // SU
+		t.scrollUp(params.get(0, 1))
+	case 'T': // SD
+		t.scrollDown(params.get(0, 1))
+	case 'X': // ECH
+		t.eraseCells(t.cursor_y, t.cursor_x, t.cursor_x+params.get(0, 1))
+	case 'Z': // CBT
+		t.backTab(params.get(0, 1))
+	case 'b': // REP
+		if t.last_printed != 0 {
+			for i := 0; i < clamp(params.get(0, 1), 0, t.width*t.height); i++ {
+				t.print(t.last_printed)
+			}
+		}
+	case 'c': // DA
+		t.respond("\x1b[?62;22c")
+	case 'd': // VPA
+		t.moveCursor(t.cursor_x, params.get(0, 1)-1)
+	case 'g': // TBC
+		switch params.get(0, 0) {
+		case 0:
+			t.tab_stops[t.cursor_x] = false
+		case 3:
+			for x := range t.tab_stops {
+				t.tab_stops[x] = false
+			}
+		}
+	case 'h': // SM
+		t.setModes(params, true)
+	case 'l': // RM
+		t.setModes(params, false)
+	case 'm': // SGR
+		t.selectGraphicRendition(params)
+	case 'n': // DSR
+		switch params.get(0, 0) {
+		case 5:
+			t.respond("\x1b[0n")
+		case 6:
+			y := t.cursor_y
+			if t.origin_mode {
+				y -= t.scroll_top
+			}
+			t.respond(fmt.Sprintf("\x1b[%d;%dR", y+1, t.cursor_x+1))
+		}
+	case 'r': // DECSTBM
+		top := params.get(0, 1) - 1
+		bottom := clamp(params.get(1, t.height), 1, t.height) - 1
+		if top < bottom {
+			t.scroll_top, t.scroll_bot = top, bottom
+			t.moveCursor(0, 0)
+		}
+	case 's': // SCOSC
+		t.saveCursor()
+	case 'u': // SCORC
+		t.restoreCursor()
+	}
+}
+
+func (t *Terminal) setModes(params csiParams, enable bool) {
+	for i := range params {
+		switch params.get(i, 0) {
+		case 4: // IRM
+			t.insert_mode = enable
+		case 20: // LNM
+			t.newline_mode = enable
+		}
+	}
+}
+
+func (t *Terminal) setPrivateModes(params csiParams, enable bool) {
+	for i := range params {
+		switch params.get(i, 0) {
+		case 1: // DECCKM
+			t.app_cursor_keys = enable
+		case 6: // DECOM
+			t.origin_mode = enable
+			t.moveCursor(0, 0)
+		case 7: // DECAWM
+			t.auto_wrap = enable
+			t.wrap_pending = false
+		case 25: // DECTCEM
+			t.cursor_visible = enable
+		case 47, 1047:
+			t.switchScreen(enable, false)
+		case 1048:
+			if enable {
+				t.saveCursor()
+			} else {
+				t.restoreCursor()
+			}
+		case 1049:
+			if enable {
+				t.saveCursor()
+				t.switchScreen(true, true)
+			} else {
+				t.switchScreen(false, false)
+				t.restoreCursor()
+			}
+		case 2004:
+			t.bracketed_paste = enable
+		}
+	}
+}
+
+var ansiColors = []tcell.Color{
+	tcell.ColorBlack, tcell.ColorMaroon, tcell.ColorGreen, tcell.ColorOlive,
+	tcell.ColorNavy, tcell.ColorPurple, tcell.ColorTeal, tcell.ColorSilver,
+	tcell.ColorGray, tcell.ColorRed, tcell.ColorLime, tcell.ColorYellow,
+	tcell.ColorBlue, tcell.ColorFuchsia, tcell.ColorAqua, tcell.ColorWhite,
+}
+
+func (t *Terminal) selectGraphicRendition(params csiParams) {
+	if len(params) == 0 {
+		t.style = tcell.StyleDefault
+		return
+	}
+	for i := 0; i < len(params); i++ {
+		code := params.get(i, 0)
+		switch {
+		case code == 0:
+			t.style = tcell.StyleDefault
+		case code == 1:
+			t.style = t.style.Bold(true)
+		case code == 2:
+			t.style = t.style.Dim(true)
+		case code == 3:
+			t.style = t.style.Italic(true)
+		case code == 4:
+			t.style = t.style.Underline(true)
+		case code == 5 || code == 6:
+			t.style = t.style.Blink(true)
+		case code == 7:
+			t.style = t.style.Reverse(true)
+		case code == 9:
+			t.style = t.style.StrikeThrough(true)
+		case code == 22:
+			t.style = t.style.Bold(false).Dim(false)
+		case code == 23:
+			t.style = t.style.Italic(false)
+		case code == 24:
+			t.style = t.style.Underline(false)
+		case code == 25:
+			t.style = t.style.Blink(false)
+		case code == 27:
+			t.style = t.style.Reverse(false)
+		case code == 29:
+			t.style = t.style.StrikeThrough(false)
+		case code >= 30 && code <= 37:
+			t.style = t.style.Foreground(ansiColors[code-30])
+		case code == 38:
+			var color tcell.Color
+			color, i = extendedColor(params, i)
+			t.style = t.style.Foreground(color)
+		case code == 39:
+			t.style = t.style.Foreground(tcell.ColorDefault)
+		case code >= 40 && code <= 47:
+			t.style = t.style.Background(ansiColors[code-40])
+		case code == 48:
+			var color tcell.Color
+			color, i = extendedColor(params, i)
+			t.style = t.style.Background(color)
+		case code == 49:
+			t.style = t.style.Background(tcell.ColorDefault)
+		case code >= 90 && code <= 97:
+			t.style = t.style.Foreground(ansiColors[code-90+8])
+		case code >= 100 && code <= 107:
+			t.style = t.style.Background(ansiColors[code-100+8])
+		}
+	}
+}
+
+// extendedColor parses both "38;5;n" and "38:5:n" forms (and their 24 bit "2;r;g;b" variants),
+// returning the index of the last parameter it used
+func extendedColor(params csiParams, i int) (tcell.Color, int) {
+	values := params[i][1:]
+	last := i
+	if len(values) == 0 {
+		// ';' separated, the color takes the following parameters
+		for j := i + 1; j < len(params) && j <= i+4; j++ {
+			values = append(values, params.get(j, 0))
+		}
+		switch {
+		case len(values) >= 2 && values[0] == 5:
+			last = i + 2
+		case len(values) >= 4 && values[0] == 2:
+			last = i + 4
+		default:
+			return tcell.ColorDefault, len(params)
+		}
+	} else if len(values) == 5 && values[0] == 2 {
+		// "38:2:colorspace:r:g:b" with the colorspace id
+		values = append(values[:1], values[2:]...)
+	}
+	switch {
+	case len(values) >= 2 && values[0] == 5:
+		return tcell.PaletteColor(clamp(values[1], 0, 255)), last
+	case len(values) >= 4 && values[0] == 2:
+		return tcell.NewRGBColor(int32(clamp(values[1], 0, 255)), int32(clamp(values[2], 0, 255)), int32(clamp(values[3], 0, 255))), last
+	}
+	return tcell.ColorDefault, last
+}
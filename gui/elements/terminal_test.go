@@ -0,0 +1,154 @@
+package elements
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func writeTerminal(t *testing.T, width, height int, output string) *Terminal {
+	t.Helper()
+	terminal := NewTerminal(width, height)
+	terminal.Write([]byte(output))
+	return terminal
+}
+
+func assertLines(t *testing.T, terminal *Terminal, expected ...string) {
+	t.Helper()
+	snapshot := terminal.Snapshot()
+	for y, line := range expected {
+		if actual := snapshot.Line(y); actual != line {
+			t.Errorf("line %d: expected %q, got %q", y, line, actual)
+		}
+	}
+}
+
+func assertCursor(t *testing.T, terminal *Terminal, x, y int) {
+	t.Helper()
+	snapshot := terminal.Snapshot()
+	if snapshot.CursorX != x || snapshot.CursorY != y {
+		t.Errorf("expected cursor at (%d,%d), got (%d,%d)", x, y, snapshot.CursorX, snapshot.CursorY)
+	}
+}
+
+func TestTerminalWrapAndScroll(t *testing.T) {
+	terminal := writeTerminal(t, 5, 3, "abcdefg\r\nhi\r\njk\r\nlm")
+	assertLines(t, terminal, "hi", "jk", "lm")
+	terminal.ScrollBack(2)
+	assertLines(t, terminal, "abcde", "fg", "hi")
+	if terminal.Snapshot().CursorVisible {
+		t.Error("cursor shouldn't be visible while browsing the scrollback")
+	}
+}
+
+func TestTerminalPendingWrap(t *testing.T) {
+	// writing the last column doesn't wrap until the next printable character
+	terminal := writeTerminal(t, 3, 2, "abc\r\nd")
+	assertLines(t, terminal, "abc", "d")
+	assertCursor(t, terminal, 1, 1)
+}
+
+func TestTerminalCursorMovementAndErase(t *testing.T) {
+	terminal := writeTerminal(t, 10, 3, "0123456789\x1b[2;3Hxy\x1b[1;5H\x1b[K\x1b[3;1Hend\x1b[2D\x1b[1P")
+	assertLines(t, terminal, "0123", "  xy", "ed")
+	assertCursor(t, terminal, 1, 2)
+}
+
+func TestTerminalAlternateScreen(t *testing.T) {
+	terminal := writeTerminal(t, 10, 2, "shell$ \x1b[?1049h\x1b[Hvim")
+	assertLines(t, terminal, "vim", "")
+	terminal.Write([]byte("\x1b[?1049l"))
+	assertLines(t, terminal, "shell$", "")
+	assertCursor(t, terminal, 7, 0)
+}
+
+func TestTerminalScrollRegion(t *testing.T) {
+	terminal := writeTerminal(t, 5, 4, "top\r\n1\r\n2\r\nbot\x1b[2;3r\x1b[3;1H\n")
+	assertLines(t, terminal, "top", "2", "", "bot")
+	terminal.Write([]byte("\x1b[2;1H\x1bM"))
+	assertLines(t, terminal, "top", "", "2", "bot")
+}
+
+func TestTerminalSplitSequencesAndUtf8(t *testing.T) {
+	terminal := NewTerminal(10, 1)
+	// escape sequences and runes split between reads
+	for _, chunk := range []string{"\x1b", "[3", "1m", "\xe2\x94", "\x80", "日本"} {
+		terminal.Write([]byte(chunk))
+	}
+	assertLines(t, terminal, "─日本")
+	assertCursor(t, terminal, 5, 0)
+	snapshot := terminal.Snapshot()
+	if _, style := snapshot.Cell(0, 0); style != tcell.StyleDefault.Foreground(tcell.ColorMaroon) {
+		t.Errorf("expected red foreground, got %v", style)
+	}
+}
+
+func TestTerminalSgrColors(t *testing.T) {
+	terminal := writeTerminal(t, 10, 1, "\x1b[1;38;5;208;48;2;1;2;3ma\x1b[38:2::4:5:6mb\x1b[0mc")
+	snapshot := terminal.Snapshot()
+	expected := []tcell.Style{
+		tcell.StyleDefault.Bold(true).Foreground(tcell.PaletteColor(208)).Background(tcell.NewRGBColor(1, 2, 3)),
+		tcell.StyleDefault.Bold(true).Foreground(tcell.NewRGBColor(4, 5, 6)).Background(tcell.NewRGBColor(1, 2, 3)),
+		tcell.StyleDefault,
+	}
+	for x, style := range expected {
+		if _, actual := snapshot.Cell(x, 0); actual != style {
+			t.Errorf("cell %d: expected style %v, got %v", x, style, actual)
+		}
+	}
+}
+
+func TestTerminalLineDrawing(t *testing.T) {
+	terminal := writeTerminal(t, 5, 1, "\x1b(0lqk\x1b(Bq")
+	assertLines(t, terminal, "┌─┐q")
+}
+
+func TestTerminalResponses(t *testing.T) {
+	terminal := NewTerminal(10, 5)
+	responses := ""
+	terminal.SetResponder(func(response []byte) { responses += string(response) })
+	terminal.Write([]byte("\x1b[3;4H\x1b[6n\x1b]0;title\x07"))
+	if responses != "\x1b[3;4R" {
+		t.Errorf("unexpected cursor position report %q", responses)
+	}
+	if terminal.Title() != "title" {
+		t.Errorf("unexpected title %q", terminal.Title())
+	}
+}
+
+func TestTerminalResize(t *testing.T) {
+	terminal := writeTerminal(t, 5, 3, "a\r\nb\r\nc")
+	terminal.Resize(4, 2)
+	assertLines(t, terminal, "b", "c")
+	assertCursor(t, terminal, 1, 1)
+	terminal.ScrollBack(1)
+	assertLines(t, terminal, "a", "b")
+}
+
+func TestTerminalEncodeKey(t *testing.T) {
+	terminal := NewTerminal(10, 1)
+	cases := []struct {
+		ev       *tcell.EventKey
+		expected string
+	}{
+		{tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone), "x"},
+		{tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModAlt), "\x1bx"},
+		{tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), "\r"},
+		{tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone), "\x7f"},
+		{tcell.NewEventKey(tcell.KeyCtrlW, 0, tcell.ModCtrl), "\x17"},
+		{tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone), "\x1b[A"},
+		{tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModCtrl), "\x1b[1;5C"},
+		{tcell.NewEventKey(tcell.KeyF1, 0, tcell.ModNone), "\x1bOP"},
+		{tcell.NewEventKey(tcell.KeyF12, 0, tcell.ModShift), "\x1b[24;2~"},
+		{tcell.NewEventKey(tcell.KeyDelete, 0, tcell.ModNone), "\x1b[3~"},
+	}
+	for _, c := range cases {
+		if actual := string(terminal.EncodeKey(c.ev)); actual != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.ev.Name(), c.expected, actual)
+		}
+	}
+	terminal.Write([]byte("\x1b[?1h"))
+	if actual := string(terminal.EncodeKey(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone))); actual != "\x1bOA" {
+		t.Errorf("expected application cursor key, got %q", actual)
+	}
+}
@@ -52,11 +52,31 @@ func Draw() error {
 		case window.ResumeWindowsEvent:
 			view.CurrentView().ResumeWindows()
 		case window.ChangeToContainerShellEvent:
-			view.ChangeToSubshell(bg_context, ev.ContainerId)
+			view.ChangeToSubshell(bg_context, ev.ContainerId, ev.Exec)
+		case window.ChangeToAttachEvent:
+			view.ChangeToAttachedSubshell(bg_context, ev.ContainerId)
+		case window.ResumeSubshellsEvent:
+			view.ResumeSubshells(bg_context)
+		case window.ChangeToCommandOutputEvent:
+			view.ChangeToCommandOutput(bg_context, ev.Command, ev.Targets)
+		case window.ChangeToFileBrowserEvent:
+			view.ChangeToFileBrowser(bg_context, ev.ContainerId)
+		case window.ChangeToDiffEvent:
+			view.ChangeToDiff(bg_context, ev.ContainerId)
+		case window.ChangeToTopEvent:
+			view.ChangeToTop(bg_context, ev.ContainerId)
+		case window.ChangeToPagerEvent:
+			view.ChangeToPager(bg_context, ev.Title, ev.Contents, ev.Note)
+		case window.ChangeToCastPlayerEvent:
+			view.ChangeToCastPlayer(bg_context, ev.Path)
+		case window.ChangeToDebugShellEvent:
+			view.ChangeToDebugSubshell(bg_context, ev.ContainerId)
 		case window.ChangeToFileEdittorEvent:
 			view.ChangeToFileEdittor(bg_context)
+		case window.ChangeToContainerFileEdittorEvent:
+			view.ChangeToContainerFileEdittor(bg_context, ev.ContainerId, ev.Path)
 		case window.ChangeToLogsWindowEvent:
-			view.ChangeToLogView(bg_context, ev.ContainerId)
+			view.ChangeToLogView(bg_context, ev.ContainerIds)
 		case window.ChangeToMainHelpEvent:
 			view.DisplayMainHelp(bg_context)
 		case window.ChangeToLogsHelpEvent:
@@ -65,6 +85,16 @@ func Draw() error {
 			view.DisplayEdittorHelp(bg_context)
 		case window.ChangeToErrorEvent:
 			view.ChangeToErrorView(bg_context, ev.Message)
+		case window.ChangeToTextEvent:
+			view.ChangeToTextView(bg_context, ev.Title, ev.Message)
+		case window.ChangeToConfirmEvent:
+			view.ChangeToConfirmView(bg_context, ev.Prompt, ev.ConfirmText, ev.Receiver, ev.Message)
+		case window.ChangeToMenuEvent:
+			view.ChangeToMenuView(bg_context, ev.Title, ev.Items, ev.X, ev.Y, ev.Receiver)
+		case window.ChangeToPromptEvent:
+			view.ChangeToPromptView(bg_context, ev.Title, ev.InitialValue, ev.History, ev.Receiver, ev.MessageGenerator)
+		case window.ChangeToFormEvent:
+			view.ChangeToFormView(bg_context, ev.Title, ev.Labels, ev.Values, ev.Actions, ev.Receiver)
 		case window.ReturnUpperViewEvent:
 			view.ReturnToUpperView()
 		case window.UpdateDockerCompose:
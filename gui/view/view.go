@@ -51,6 +51,9 @@ func (view *View) Open(bg_context context.Context) {
 	for _, win := range view.windows {
 		win.Open(view.view_ctx)
 	}
+	if view.mouse_settings != 0 {
+		window.GetScreen().EnableMouse(view.mouse_settings)
+	}
 }
 
 func (view *View) Resize() {
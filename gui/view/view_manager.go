@@ -2,17 +2,28 @@ package view
 
 import (
 	"context"
+	"dc-top/docker"
 	"dc-top/docker/compose"
 	"dc-top/gui/view/window"
 	"dc-top/gui/view/window/bar_window"
+	"dc-top/gui/view/window/cast_player_window"
+	"dc-top/gui/view/window/command_output_window"
+	"dc-top/gui/view/window/confirm_window"
 	"dc-top/gui/view/window/container_logs_window"
 	"dc-top/gui/view/window/containers_window"
+	"dc-top/gui/view/window/diff_window"
 	"dc-top/gui/view/window/docker_info_window"
 	"dc-top/gui/view/window/edittor_window"
 	"dc-top/gui/view/window/error_window"
+	"dc-top/gui/view/window/file_browser_window"
+	"dc-top/gui/view/window/form_window"
 	"dc-top/gui/view/window/general_info_window"
 	"dc-top/gui/view/window/help_window"
+	"dc-top/gui/view/window/menu_window"
+	"dc-top/gui/view/window/pager_window"
+	"dc-top/gui/view/window/prompt_window"
 	"dc-top/gui/view/window/subshell_window"
+	"dc-top/gui/view/window/top_window"
 	"log"
 	"os"
 	"sync"
@@ -31,6 +42,17 @@ const (
 	edittor_help
 	subshell
 	err
+	text
+	confirm
+	menu
+	prompt
+	form
+	player
+	command_output
+	files
+	pager
+	diff
+	top
 	none
 )
 
@@ -96,13 +118,13 @@ func DisplayMainHelp(bg_context context.Context) {
 	changeToHelpView(bg_context, main_help, main, help_window.MainControls())
 }
 
-func ChangeToLogView(bg_context context.Context, container_id string) {
+func ChangeToLogView(bg_context context.Context, container_ids []string) {
 	log.Printf("Changing to logs")
 
 	window.GetScreen().Clear()
 	window.GetScreen().Show()
 
-	logs_window := container_logs_window.NewContainerLogsWindow(container_id)
+	logs_window := container_logs_window.NewContainerLogsWindow(container_ids)
 	bar_dimensions_generator := func() window.Dimensions {
 		x1, y1, x2, y2 := window.LogsBarWindowSize()
 		return window.NewDimensions(x1, y1, x2, y2, false)
@@ -128,6 +150,19 @@ func ChangeToFileEdittor(bg_context context.Context) {
 		log.Printf("Failed to open file %s", compose.DcYamlPath())
 		return
 	}
+	openEdittor(bg_context, edittor_window.NewComposeFile(file), main)
+}
+
+func ChangeToContainerFileEdittor(bg_context context.Context, id string, path string) {
+	log.Printf("Changing to edittor of %s in %s", path, id)
+
+	window.GetScreen().Clear()
+	window.GetScreen().Show()
+
+	openEdittor(bg_context, edittor_window.NewContainerFile(id, path), currentViewName())
+}
+
+func openEdittor(bg_context context.Context, file edittor_window.EdittedFile, prev_view _viewName) {
 	edittor_window := edittor_window.NewEdittorWindow(file)
 
 	edittor_dimensions_generator := func() window.Dimensions {
@@ -142,25 +177,128 @@ func ChangeToFileEdittor(bg_context context.Context) {
 	}, window.Edittor,
 		0,
 		true)
-	changeView(bg_context, edittor, main, &edittor_view)
+	changeView(bg_context, edittor, prev_view, &edittor_view)
 }
 
-func ChangeToSubshell(bg_context context.Context, id string) {
+func ChangeToSubshell(bg_context context.Context, id string, exec docker.ExecSpec) {
 	log.Printf("Changing to subshell")
+	openSubshell(bg_context, subshell_window.NewExecSession(id, exec))
+}
+
+func ChangeToDebugSubshell(bg_context context.Context, id string) {
+	log.Printf("Changing to debug subshell")
+	openSubshell(bg_context, subshell_window.NewDebugSession(id))
+}
+
+func ChangeToAttachedSubshell(bg_context context.Context, id string) {
+	log.Printf("Changing to attached subshell")
+	openSubshell(bg_context, subshell_window.NewAttachSession(id))
+}
+
+// ResumeSubshells returns to the sessions that were left running in the background
+func ResumeSubshells(bg_context context.Context) {
+	log.Printf("Resuming subshells")
+	if !subshell_window.HasSessions() {
+		bar_window.Info([]rune("No shell sessions are open"))
+		return
+	}
+	openSubshell(bg_context, nil)
+}
 
+func openSubshell(bg_context context.Context, new_session *subshell_window.Session) {
 	window.GetScreen().Clear()
 	window.GetScreen().Show()
 
-	subshell_window := subshell_window.NewSubshellWindow(id)
-
+	shell_window := subshell_window.NewSubshellWindow(bg_context, new_session)
 	subshell_view := NewView(map[window.WindowType]window.Window{
-		window.Subshell: &subshell_window,
+		window.Subshell: &shell_window,
 	}, window.Subshell,
 		0,
 		false)
 	changeView(bg_context, subshell, main, &subshell_view)
 }
 
+func ChangeToCastPlayer(bg_context context.Context, path string) {
+	log.Printf("Changing to cast player")
+	window.GetScreen().Clear()
+	window.GetScreen().Show()
+
+	player_window := cast_player_window.NewCastPlayerWindow(path)
+	player_view := NewView(map[window.WindowType]window.Window{
+		window.CastPlayer: &player_window,
+	}, window.CastPlayer,
+		0,
+		false)
+	changeView(bg_context, player, main, &player_view)
+}
+
+func ChangeToCommandOutput(bg_context context.Context, command string, targets []window.CommandTarget) {
+	log.Printf("Changing to command output")
+	window.GetScreen().Clear()
+	window.GetScreen().Show()
+
+	output_window := command_output_window.NewCommandOutputWindow(command, targets)
+	output_view := NewView(map[window.WindowType]window.Window{
+		window.CommandOutput: &output_window,
+	}, window.CommandOutput,
+		0,
+		false)
+	changeView(bg_context, command_output, main, &output_view)
+}
+
+func ChangeToFileBrowser(bg_context context.Context, container_id string) {
+	log.Printf("Changing to file browser")
+	window.GetScreen().Clear()
+	window.GetScreen().Show()
+
+	browser_window := file_browser_window.NewFileBrowserWindow(container_id)
+	browser_view := NewView(map[window.WindowType]window.Window{
+		window.FileBrowser: &browser_window,
+	}, window.FileBrowser,
+		0,
+		false)
+	changeView(bg_context, files, main, &browser_view)
+}
+
+func ChangeToDiff(bg_context context.Context, container_id string) {
+	log.Printf("Changing to diff")
+	window.GetScreen().Clear()
+	window.GetScreen().Show()
+
+	diff_window := diff_window.NewDiffWindow(container_id)
+	diff_view := NewView(map[window.WindowType]window.Window{
+		window.Diff: &diff_window,
+	}, window.Diff,
+		0,
+		false)
+	changeView(bg_context, diff, main, &diff_view)
+}
+
+func ChangeToTop(bg_context context.Context, container_id string) {
+	log.Printf("Changing to top")
+	window.GetScreen().Clear()
+	window.GetScreen().Show()
+
+	top_window := top_window.NewTopWindow(container_id)
+	top_view := NewView(map[window.WindowType]window.Window{
+		window.Top: &top_window,
+	}, window.Top,
+		0,
+		false)
+	changeView(bg_context, top, main, &top_view)
+}
+
+func ChangeToPager(bg_context context.Context, title string, contents []byte, note string) {
+	log.Printf("Changing to pager")
+	pager_window := pager_window.NewPagerWindow(title, contents, note)
+	pager_view := NewView(map[window.WindowType]window.Window{
+		window.Pager: &pager_window,
+	}, window.Pager,
+		0,
+		false)
+	changeView(bg_context, pager, currentViewName(), &pager_view)
+}
+
 func ChangeToErrorView(bg_context context.Context, message []byte) {
 	log.Printf("Changing to error")
 	error_window := error_window.NewErrorWindow(message)
@@ -172,6 +310,61 @@ func ChangeToErrorView(bg_context context.Context, message []byte) {
 	changeView(bg_context, err, currentViewName(), &error_view)
 }
 
+func ChangeToTextView(bg_context context.Context, title string, message []byte) {
+	log.Printf("Changing to text")
+	text_window := error_window.NewTextWindow(title, message)
+	text_view := NewView(map[window.WindowType]window.Window{
+		window.Error: &text_window,
+	}, window.Error,
+		0,
+		false)
+	changeView(bg_context, text, currentViewName(), &text_view)
+}
+
+func ChangeToConfirmView(bg_context context.Context, prompt string, confirm_text string, receiver window.WindowType, message interface{}) {
+	log.Printf("Changing to confirm")
+	confirm_window := confirm_window.NewConfirmWindow(prompt, confirm_text, receiver, message)
+	confirm_view := NewView(map[window.WindowType]window.Window{
+		window.Confirm: &confirm_window,
+	}, window.Confirm,
+		0,
+		false)
+	changeView(bg_context, confirm, currentViewName(), &confirm_view)
+}
+
+func ChangeToMenuView(bg_context context.Context, title string, items []window.MenuItem, x, y int, receiver window.WindowType) {
+	log.Printf("Changing to menu")
+	menu_window := menu_window.NewMenuWindow(title, items, x, y, receiver)
+	menu_view := NewView(map[window.WindowType]window.Window{
+		window.Menu: &menu_window,
+	}, window.Menu,
+		containers_window.MouseOptions,
+		false)
+	changeView(bg_context, menu, currentViewName(), &menu_view)
+}
+
+func ChangeToPromptView(bg_context context.Context, title string, initial_value string, history []string, receiver window.WindowType, message_generator func(string) interface{}) {
+	log.Printf("Changing to prompt")
+	prompt_window := prompt_window.NewPromptWindow(title, initial_value, history, receiver, message_generator)
+	prompt_view := NewView(map[window.WindowType]window.Window{
+		window.Prompt: &prompt_window,
+	}, window.Prompt,
+		0,
+		false)
+	changeView(bg_context, prompt, currentViewName(), &prompt_view)
+}
+
+func ChangeToFormView(bg_context context.Context, title string, labels []string, values []string, actions []window.FormAction, receiver window.WindowType) {
+	log.Printf("Changing to form")
+	form_window := form_window.NewFormWindow(title, labels, values, actions, receiver)
+	form_view := NewView(map[window.WindowType]window.Window{
+		window.Form: &form_window,
+	}, window.Form,
+		0,
+		false)
+	changeView(bg_context, form, currentViewName(), &form_view)
+}
+
 func DisplayLogHelp(bg_context context.Context) {
 	log.Printf("Changing to log help")
 	changeToHelpView(bg_context, logs_help, logs, help_window.LogControls())
@@ -194,6 +387,8 @@ func HandleMouseEvent(ev *tcell.EventMouse) {
 	switch currentViewName() {
 	case main:
 		DefaultView().GetWindow(window.ContainersHolder).MousePress(*ev)
+	case menu:
+		_views[menu].GetWindow(window.Menu).MousePress(*ev)
 	}
 }
 
@@ -219,6 +414,7 @@ func CloseAll() {
 	for _, view := range _views {
 		view.Close()
 	}
+	subshell_window.CloseSessions()
 }
 
 func changeToHelpView(bg_context context.Context, new_view_key, prev_view_key _viewName, controls []help_window.Control) {
@@ -0,0 +1,175 @@
+package cast_player_window
+
+import (
+	"dc-top/gui/elements"
+	"fmt"
+	"log"
+	"time"
+)
+
+type playerState struct {
+	header elements.CastHeader
+	events []elements.CastEvent
+	// times of the events after shortening idle gaps
+	times    []time.Duration
+	duration time.Duration
+
+	terminal    *elements.Terminal
+	next_event  int
+	speed_index int
+	is_paused   bool
+	// while playing, the position is `offset` plus the time since `started` at the current speed
+	offset  time.Duration
+	started time.Time
+}
+
+func newPlayerState(header elements.CastHeader, events []elements.CastEvent) *playerState {
+	idle_time_limit := header.IdleTimeLimit
+	if idle_time_limit <= 0 {
+		idle_time_limit = defaultIdleTimeLimit
+	}
+	times := make([]time.Duration, len(events))
+	var previous, current float64
+	for i, event := range events {
+		gap := event.Time - previous
+		if gap > idle_time_limit {
+			gap = idle_time_limit
+		}
+		if gap > 0 {
+			current += gap
+		}
+		previous = event.Time
+		times[i] = time.Duration(current * float64(time.Second))
+	}
+	state := &playerState{
+		header:      header,
+		events:      events,
+		times:       times,
+		speed_index: 2,
+		started:     time.Now(),
+	}
+	if len(times) > 0 {
+		state.duration = times[len(times)-1]
+	} else {
+		state.is_paused = true
+	}
+	state.reset()
+	return state
+}
+
+func (state *playerState) reset() {
+	state.terminal = elements.NewTerminal(state.header.Width, state.header.Height)
+	state.next_event = 0
+}
+
+func (state *playerState) speed() float64 {
+	return speeds[state.speed_index]
+}
+
+func (state *playerState) position() time.Duration {
+	if state.is_paused {
+		return state.offset
+	}
+	position := state.offset + time.Duration(float64(time.Since(state.started))*state.speed())
+	if position > state.duration {
+		return state.duration
+	}
+	return position
+}
+
+// untilNextEvent is the real time to wait for the next event, false if there's nothing to wait for
+func (state *playerState) untilNextEvent() (time.Duration, bool) {
+	if state.is_paused || state.next_event >= len(state.events) {
+		return 0, false
+	}
+	wait := state.times[state.next_event] - state.position()
+	if wait < 0 {
+		wait = 0
+	}
+	return time.Duration(float64(wait) / state.speed()), true
+}
+
+// advance applies the events up to the current position, and pauses at the end of the recording
+func (state *playerState) advance() {
+	state.applyUntil(state.position())
+	if state.next_event >= len(state.events) && !state.is_paused {
+		state.offset = state.duration
+		state.is_paused = true
+	}
+}
+
+func (state *playerState) applyUntil(position time.Duration) {
+	for ; state.next_event < len(state.events) && state.times[state.next_event] <= position; state.next_event++ {
+		event := state.events[state.next_event]
+		switch event.Type {
+		case elements.CastOutput:
+			state.terminal.Write([]byte(event.Data))
+		case elements.CastResize:
+			width, height, err := elements.ParseCastSize(event.Data)
+			if err != nil {
+				log.Printf("Ignoring bad resize event: %s", err)
+				continue
+			}
+			state.terminal.Resize(width, height)
+		}
+	}
+}
+
+// seek replays the recording from the start when going backwards, the terminal can't be rewound
+func (state *playerState) seek(position time.Duration) {
+	if position < 0 {
+		position = 0
+	}
+	if position > state.duration {
+		position = state.duration
+	}
+	if position < state.position() {
+		state.reset()
+	}
+	state.applyUntil(position)
+	state.offset = position
+	state.started = time.Now()
+	if state.next_event >= len(state.events) {
+		state.is_paused = true
+	}
+}
+
+func (state *playerState) togglePause() {
+	if state.is_paused {
+		if state.offset >= state.duration {
+			state.seek(0)
+		}
+		state.started = time.Now()
+		state.is_paused = false
+	} else {
+		state.offset = state.position()
+		state.is_paused = true
+	}
+}
+
+func (state *playerState) setSpeed(speed_index int) {
+	if speed_index < 0 || speed_index >= len(speeds) {
+		return
+	}
+	state.offset = state.position()
+	state.started = time.Now()
+	state.speed_index = speed_index
+}
+
+func (state *playerState) status() string {
+	icon := "▶"
+	if state.is_paused {
+		icon = "⏸"
+	}
+	title := state.header.Title
+	if title == "" {
+		title = "recording"
+	}
+	return fmt.Sprintf(" %s %s / %s  %gx  %s  (Space pause, ←/→ seek, +/- speed, q quit)",
+		icon, formatDuration(state.position()), formatDuration(state.duration), state.speed(), title)
+}
+
+func formatDuration(d time.Duration) string {
+	seconds := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
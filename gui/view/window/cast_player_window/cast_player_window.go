@@ -0,0 +1,198 @@
+package cast_player_window
+
+import (
+	"context"
+	"dc-top/gui/elements"
+	"dc-top/gui/view/window"
+	"dc-top/gui/view/window/bar_window"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// gaps longer than this are shortened when replaying, unless the recording sets its own limit
+const defaultIdleTimeLimit = 2.0
+
+const seekStep = 5 * time.Second
+
+var speeds = []float64{0.25, 0.5, 1, 2, 4, 8, 16}
+
+// CastPlayerWindow replays an asciicast recording
+type CastPlayerWindow struct {
+	window_ctx    context.Context
+	window_cancel context.CancelFunc
+
+	path                 string
+	dimensions_generator func() window.Dimensions
+	keyboard_ch          chan tcell.EventKey
+	resize_ch            chan interface{}
+	enable_toggle        chan bool
+}
+
+func NewCastPlayerWindow(path string) CastPlayerWindow {
+	return CastPlayerWindow{
+		path: path,
+		dimensions_generator: func() window.Dimensions {
+			w, h := window.GetScreen().Size()
+			return window.NewDimensions(0, 0, w-1, h-1, false)
+		},
+		keyboard_ch:   make(chan tcell.EventKey),
+		resize_ch:     make(chan interface{}),
+		enable_toggle: make(chan bool),
+	}
+}
+
+func (w *CastPlayerWindow) Open(view_ctx context.Context) {
+	w.window_ctx, w.window_cancel = context.WithCancel(view_ctx)
+	header, events, err := elements.ReadCast(w.path)
+	if err != nil {
+		log.Printf("Failed to read recording %s: %s", w.path, err)
+		w.window_cancel()
+		bar_window.Err([]rune(fmt.Sprintf("Failed to read recording %s: %s", filepath.Base(w.path), err)))
+		window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+		return
+	}
+	go w.main(newPlayerState(header, events))
+}
+
+func (w *CastPlayerWindow) Resize() {
+	select {
+	case w.resize_ch <- nil:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *CastPlayerWindow) KeyPress(ev tcell.EventKey) {
+	select {
+	case w.keyboard_ch <- ev:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *CastPlayerWindow) MousePress(_ tcell.EventMouse) {}
+
+func (w *CastPlayerWindow) HandleEvent(interface{}, window.WindowType) (interface{}, error) {
+	panic(1)
+}
+
+func (w *CastPlayerWindow) Disable() {
+	log.Printf("Disable CastPlayerWindow...")
+	select {
+	case w.enable_toggle <- false:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *CastPlayerWindow) Enable() {
+	log.Printf("Enable CastPlayerWindow...")
+	select {
+	case w.enable_toggle <- true:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *CastPlayerWindow) Close() {
+	w.window_cancel()
+}
+
+func (w *CastPlayerWindow) main(state *playerState) {
+	window.GetScreen().Clear()
+	is_enabled := true
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	// keeps the clock in the status line moving between events
+	clock := time.NewTicker(time.Second)
+	defer clock.Stop()
+	for {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		var timer_ch <-chan time.Time
+		if wait, ok := state.untilNextEvent(); ok {
+			timer.Reset(wait)
+			timer_ch = timer.C
+		}
+		if is_enabled {
+			w.draw(state)
+		}
+		select {
+		case <-timer_ch:
+			state.advance()
+		case <-clock.C:
+		case ev := <-w.keyboard_ch:
+			if w.handleKeyPress(state, &ev) {
+				return
+			}
+		case <-w.resize_ch:
+			window.GetScreen().Clear()
+		case is_enabled = <-w.enable_toggle:
+		case <-w.window_ctx.Done():
+			return
+		}
+	}
+}
+
+// handleKeyPress returns true when the player is closed
+func (w *CastPlayerWindow) handleKeyPress(state *playerState, ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		w.quit()
+		return true
+	case tcell.KeyLeft:
+		state.seek(state.position() - seekStep)
+	case tcell.KeyRight:
+		state.seek(state.position() + seekStep)
+	case tcell.KeyHome:
+		state.seek(0)
+	case tcell.KeyEnd:
+		state.seek(state.duration)
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'q':
+			w.quit()
+			return true
+		case ' ':
+			state.togglePause()
+		case '+', '=':
+			state.setSpeed(state.speed_index + 1)
+		case '-':
+			state.setSpeed(state.speed_index - 1)
+		case 'g':
+			state.seek(0)
+		case 'G':
+			state.seek(state.duration)
+		}
+	}
+	return false
+}
+
+func (w *CastPlayerWindow) quit() {
+	w.window_cancel()
+	window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+}
+
+func (w *CastPlayerWindow) draw(state *playerState) {
+	screen := window.GetScreen()
+	dimensions := w.dimensions_generator()
+	terminal_dimensions := window.NewDimensions(dimensions.LeftX, dimensions.TopY, dimensions.RightX, dimensions.ButtomY-1, false)
+	snapshot := state.terminal.Snapshot()
+	window.DrawContents(&terminal_dimensions, snapshot.Cell)
+
+	status := []rune(state.status())
+	status_dimensions := window.NewDimensions(dimensions.LeftX, dimensions.ButtomY, dimensions.RightX, dimensions.ButtomY, false)
+	status_style := tcell.StyleDefault.Reverse(true)
+	window.DrawContents(&status_dimensions, func(x, _ int) (rune, tcell.Style) {
+		if x < len(status) {
+			return status[x], status_style
+		}
+		return ' ', status_style
+	})
+	screen.HideCursor()
+	screen.Show()
+}
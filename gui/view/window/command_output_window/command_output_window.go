@@ -0,0 +1,221 @@
+package command_output_window
+
+import (
+	"context"
+	"dc-top/docker"
+	"dc-top/gui/view/window"
+	"dc-top/utils"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// CommandOutputWindow runs a command in one or more containers and shows the output of each of them
+type CommandOutputWindow struct {
+	window_ctx    context.Context
+	window_cancel context.CancelFunc
+
+	command string
+	results []*containerResult
+
+	dimensions_generator func() window.Dimensions
+	keyboard_ch          chan tcell.EventKey
+	resize_ch            chan interface{}
+	enable_toggle        chan bool
+	output_ch            chan interface{}
+}
+
+func NewCommandOutputWindow(command string, targets []window.CommandTarget) CommandOutputWindow {
+	results := make([]*containerResult, len(targets))
+	for i, target := range targets {
+		results[i] = &containerResult{target: target}
+	}
+	return CommandOutputWindow{
+		command: command,
+		results: results,
+		dimensions_generator: func() window.Dimensions {
+			w, h := window.GetScreen().Size()
+			return window.NewDimensions(0, 0, w-1, h-1, true)
+		},
+		keyboard_ch:   make(chan tcell.EventKey),
+		resize_ch:     make(chan interface{}),
+		enable_toggle: make(chan bool),
+		output_ch:     make(chan interface{}, 1),
+	}
+}
+
+func (w *CommandOutputWindow) Open(view_ctx context.Context) {
+	w.window_ctx, w.window_cancel = context.WithCancel(view_ctx)
+	args := commandArgs(w.command)
+	for _, result := range w.results {
+		go w.run(result, args)
+	}
+	go w.main()
+}
+
+func (w *CommandOutputWindow) Resize() {
+	select {
+	case w.resize_ch <- nil:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *CommandOutputWindow) KeyPress(ev tcell.EventKey) {
+	select {
+	case w.keyboard_ch <- ev:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *CommandOutputWindow) MousePress(_ tcell.EventMouse) {}
+
+func (w *CommandOutputWindow) HandleEvent(interface{}, window.WindowType) (interface{}, error) {
+	panic(1)
+}
+
+func (w *CommandOutputWindow) Disable() {
+	log.Printf("Disable CommandOutputWindow...")
+	select {
+	case w.enable_toggle <- false:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *CommandOutputWindow) Enable() {
+	log.Printf("Enable CommandOutputWindow...")
+	select {
+	case w.enable_toggle <- true:
+	case <-w.window_ctx.Done():
+	}
+}
+
+// Close stops the commands that are still running
+func (w *CommandOutputWindow) Close() {
+	w.window_cancel()
+}
+
+// commandArgs runs commands that use shell syntax with `sh -c`, and others directly so they work in images without a shell
+func commandArgs(command string) []string {
+	if strings.ContainsAny(command, "|&;<>()$`*?") {
+		return []string{"sh", "-c", command}
+	}
+	return utils.SplitArgs(command)
+}
+
+func (w *CommandOutputWindow) run(result *containerResult, args []string) {
+	exit_code, err := docker.RunCommand(w.window_ctx, result.target.Id, args,
+		resultWriter{w: w, result: result}, resultWriter{w: w, result: result, is_stderr: true})
+	if w.window_ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to run '%s' in %s: %s", w.command, result.target.Name, err)
+	}
+	result.finish(exit_code, err)
+	w.outputChanged()
+}
+
+func (w *CommandOutputWindow) outputChanged() {
+	select {
+	case w.output_ch <- nil:
+	default:
+	}
+}
+
+type resultWriter struct {
+	w         *CommandOutputWindow
+	result    *containerResult
+	is_stderr bool
+}
+
+func (writer resultWriter) Write(p []byte) (int, error) {
+	writer.result.write(p, writer.is_stderr)
+	writer.w.outputChanged()
+	return len(p), nil
+}
+
+func (w *CommandOutputWindow) main() {
+	window.GetScreen().Clear()
+	is_enabled := true
+	top_row := 0
+	// the view follows the output until it's scrolled up
+	is_following := true
+	for {
+		var rows []row
+		dimensions := w.dimensions_generator()
+		for _, result := range w.results {
+			rows = append(rows, result.rows(window.Width(&dimensions))...)
+		}
+		page := window.Height(&dimensions) - 1
+		max_top := utils.Max(len(rows)-page, 0)
+		if is_following || top_row > max_top {
+			top_row = max_top
+		}
+		if is_enabled {
+			w.draw(&dimensions, rows, top_row)
+		}
+		select {
+		case <-w.output_ch:
+		case ev := <-w.keyboard_ch:
+			switch ev.Key() {
+			case tcell.KeyUp:
+				top_row--
+			case tcell.KeyDown:
+				top_row++
+			case tcell.KeyPgUp:
+				top_row -= page
+			case tcell.KeyPgDn:
+				top_row += page
+			case tcell.KeyHome:
+				top_row = 0
+			case tcell.KeyEnd:
+				top_row = max_top
+			case tcell.KeyEscape:
+				w.quit()
+				return
+			case tcell.KeyRune:
+				switch ev.Rune() {
+				case 'g':
+					top_row = 0
+				case 'G':
+					top_row = max_top
+				case 'q':
+					w.quit()
+					return
+				}
+			}
+			top_row = utils.Min(utils.Max(top_row, 0), max_top)
+			is_following = top_row == max_top
+		case <-w.resize_ch:
+			window.GetScreen().Clear()
+		case is_enabled = <-w.enable_toggle:
+		case <-w.window_ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *CommandOutputWindow) quit() {
+	w.window_cancel()
+	window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+}
+
+func (w *CommandOutputWindow) draw(dimensions *window.Dimensions, rows []row, top_row int) {
+	title := []rune(fmt.Sprintf("$ %s  (%d containers, stderr in red, q to close)", w.command, len(w.results)))
+	window.DrawContents(dimensions, func(x, y int) (rune, tcell.Style) {
+		if y == 0 {
+			if x < len(title) {
+				return title[x], tcell.StyleDefault.Bold(true)
+			}
+			return ' ', tcell.StyleDefault
+		}
+		index := top_row + y - 1
+		if index >= len(rows) || x >= len(rows[index].text) {
+			return ' ', tcell.StyleDefault
+		}
+		return rows[index].text[x], rows[index].style
+	})
+	window.GetScreen().Show()
+}
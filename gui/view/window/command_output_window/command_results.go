@@ -0,0 +1,142 @@
+package command_output_window
+
+import (
+	"bytes"
+	"dc-top/gui/view/window"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/acarl005/stripansi"
+	"github.com/gdamore/tcell/v2"
+)
+
+type commandState uint8
+
+const (
+	running commandState = iota
+	exited
+	failed
+)
+
+type outputLine struct {
+	text      string
+	is_stderr bool
+}
+
+// containerResult is the output of the command in one container, written by the stream goroutines
+type containerResult struct {
+	lock      sync.Mutex
+	target    window.CommandTarget
+	lines     []outputLine
+	partial   [2][]byte
+	state     commandState
+	exit_code int
+	err       error
+}
+
+func (result *containerResult) write(p []byte, is_stderr bool) {
+	result.lock.Lock()
+	defer result.lock.Unlock()
+	stream := 0
+	if is_stderr {
+		stream = 1
+	}
+	data := append(result.partial[stream], p...)
+	for {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			break
+		}
+		result.lines = append(result.lines, newOutputLine(data[:end], is_stderr))
+		data = data[end+1:]
+	}
+	result.partial[stream] = append([]byte(nil), data...)
+}
+
+func (result *containerResult) finish(exit_code int, err error) {
+	result.lock.Lock()
+	defer result.lock.Unlock()
+	for stream, partial := range result.partial {
+		if len(partial) > 0 {
+			result.lines = append(result.lines, newOutputLine(partial, stream == 1))
+		}
+		result.partial[stream] = nil
+	}
+	if err != nil {
+		result.state = failed
+		result.err = err
+	} else {
+		result.state = exited
+		result.exit_code = exit_code
+	}
+}
+
+func newOutputLine(line []byte, is_stderr bool) outputLine {
+	text := stripansi.Strip(strings.TrimSuffix(string(line), "\r"))
+	return outputLine{text: strings.ReplaceAll(text, "\t", "    "), is_stderr: is_stderr}
+}
+
+var (
+	headerStyle = tcell.StyleDefault.Bold(true).Foreground(tcell.ColorYellow)
+	stdoutStyle = tcell.StyleDefault
+	stderrStyle = tcell.StyleDefault.Foreground(tcell.ColorRed)
+	failedStyle = tcell.StyleDefault.Bold(true).Foreground(tcell.ColorRed)
+	okStyle     = tcell.StyleDefault.Bold(true).Foreground(tcell.ColorGreen)
+)
+
+type row struct {
+	text  []rune
+	style tcell.Style
+}
+
+// rows lays out the header and the output of the container, wrapping long lines
+func (result *containerResult) rows(width int) []row {
+	result.lock.Lock()
+	defer result.lock.Unlock()
+	status, status_style := "running...", headerStyle
+	switch result.state {
+	case exited:
+		status = fmt.Sprintf("exit code %d", result.exit_code)
+		if result.exit_code != 0 {
+			status_style = failedStyle
+		} else {
+			status_style = okStyle
+		}
+	case failed:
+		status, status_style = fmt.Sprintf("failed: %s", result.err), failedStyle
+	}
+	rows := []row{
+		{[]rune(fmt.Sprintf("── %s ──", result.target.Name)), headerStyle},
+	}
+	for _, line := range result.lines {
+		style := stdoutStyle
+		if line.is_stderr {
+			style = stderrStyle
+		}
+		rows = append(rows, wrap([]rune(line.text), width, style)...)
+	}
+	for stream, partial := range result.partial {
+		if len(partial) > 0 {
+			line := newOutputLine(partial, stream == 1)
+			style := stdoutStyle
+			if line.is_stderr {
+				style = stderrStyle
+			}
+			rows = append(rows, wrap([]rune(line.text), width, style)...)
+		}
+	}
+	return append(rows, row{[]rune("[" + status + "]"), status_style}, row{})
+}
+
+func wrap(text []rune, width int, style tcell.Style) []row {
+	if width <= 0 || len(text) <= width {
+		return []row{{text, style}}
+	}
+	var rows []row
+	for len(text) > width {
+		rows = append(rows, row{text[:width], style})
+		text = text[width:]
+	}
+	return append(rows, row{text, style})
+}
@@ -0,0 +1,160 @@
+package confirm_window
+
+import (
+	"context"
+	"dc-top/gui/elements"
+	"dc-top/gui/view/window"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ConfirmWindow is a modal that sends `message` to `receiver` only if the user confirms.
+// When `confirm_text` isn't empty the user has to type it exactly in order to confirm.
+type ConfirmWindow struct {
+	window_ctx    context.Context
+	window_cancel context.CancelFunc
+
+	prompt       string
+	confirm_text string
+	receiver     window.WindowType
+	message      interface{}
+
+	dimensions_generator func() window.Dimensions
+	confirm_box          elements.TextBox
+	is_enabled           bool
+}
+
+func NewConfirmWindow(prompt string, confirm_text string, receiver window.WindowType, message interface{}) ConfirmWindow {
+	return ConfirmWindow{
+		prompt:       prompt,
+		confirm_text: confirm_text,
+		receiver:     receiver,
+		message:      message,
+		dimensions_generator: func() window.Dimensions {
+			x1, y1, x2, y2 := window.ConfirmWindowSize()
+			return window.NewDimensions(x1, y1, x2, y2, true)
+		},
+		confirm_box: elements.NewTextBox(
+			elements.TextDrawer("> ", tcell.StyleDefault.Foreground(tcell.ColorYellow)),
+			2,
+			tcell.StyleDefault,
+			tcell.StyleDefault.Background(tcell.ColorWhite).Foreground(tcell.ColorBlack),
+			true),
+		is_enabled: true,
+	}
+}
+
+func (w *ConfirmWindow) Open(view_ctx context.Context) {
+	log.Println("Opening confirm")
+	w.window_ctx, w.window_cancel = context.WithCancel(view_ctx)
+	w.drawConfirm()
+}
+
+func (w *ConfirmWindow) Resize() {
+	w.drawConfirm()
+}
+
+func (w *ConfirmWindow) KeyPress(ev tcell.EventKey) {
+	if w.confirm_text == "" {
+		if ev.Key() == tcell.KeyRune && (ev.Rune() == 'y' || ev.Rune() == 'Y') {
+			w.confirm()
+		} else {
+			w.cancel()
+		}
+		return
+	}
+
+	switch ev.Key() {
+	case tcell.KeyEnter:
+		if w.confirm_box.Value() == w.confirm_text {
+			w.confirm()
+		} else {
+			w.cancel()
+		}
+		return
+	case tcell.KeyEscape:
+		w.cancel()
+		return
+	case tcell.KeyCtrlD:
+		w.cancel()
+		return
+	default:
+		w.confirm_box.HandleKey(&ev)
+	}
+	w.drawConfirm()
+}
+
+func (w *ConfirmWindow) MousePress(_ tcell.EventMouse) {}
+
+func (w *ConfirmWindow) HandleEvent(interface{}, window.WindowType) (interface{}, error) {
+	window.ExitIfErr(errors.New("shouldn't have gotten here"))
+	panic(1)
+}
+
+func (w *ConfirmWindow) Disable() {
+	log.Printf("Disable ConfirmWindow...")
+	w.is_enabled = false
+}
+
+func (w *ConfirmWindow) Enable() {
+	log.Printf("Enable ConfirmWindow...")
+	w.is_enabled = true
+	w.drawConfirm()
+}
+
+func (w *ConfirmWindow) Close() {
+	w.window_cancel()
+}
+
+func (w *ConfirmWindow) confirm() {
+	log.Printf("Confirmed '%s'", w.prompt)
+	screen := window.GetScreen()
+	screen.PostEvent(window.NewReturnUpperViewEvent())
+	screen.PostEvent(window.NewMessageEvent(w.receiver, window.Confirm, w.message))
+}
+
+func (w *ConfirmWindow) cancel() {
+	log.Printf("Cancelled '%s'", w.prompt)
+	window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+}
+
+func (w *ConfirmWindow) drawConfirm() {
+	if !w.is_enabled {
+		return
+	}
+	dimensions := w.dimensions_generator()
+
+	lines := []elements.StringStyler{
+		elements.TextDrawer("Confirm", tcell.StyleDefault.Bold(true).Underline(true)),
+		elements.EmptyDrawer(),
+	}
+	for _, prompt_line := range strings.Split(w.prompt, "\n") {
+		lines = append(lines, elements.TextDrawer(prompt_line, tcell.StyleDefault))
+	}
+	lines = append(lines, elements.EmptyDrawer())
+	if w.confirm_text == "" {
+		lines = append(lines, elements.TextDrawer("Press 'y' to confirm, any other key to cancel", tcell.StyleDefault.Foreground(tcell.ColorYellow)))
+	} else {
+		lines = append(lines,
+			elements.TextDrawer(fmt.Sprintf("Type '%s' and press Enter to confirm, Esc to cancel", w.confirm_text), tcell.StyleDefault.Foreground(tcell.ColorYellow)),
+			w.confirm_box.Style(),
+		)
+	}
+
+	if window.Height(&dimensions) > len(lines)+1 {
+		dimensions.ButtomY = dimensions.TopY + len(lines) + 1
+	}
+
+	var drawer = func(x, y int) (rune, tcell.Style) {
+		if y < len(lines) {
+			return lines[y](x)
+		}
+		return ' ', tcell.StyleDefault
+	}
+	window.DrawContents(&dimensions, drawer)
+	window.GetScreen().Show()
+}
@@ -7,30 +7,70 @@ import (
 	"dc-top/gui/view/window/bar_window"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 )
 
+var logsRangeLabels = []string{"Since", "Until"}
+
+// logsRangeRequest is sent by the time range form
+type logsRangeRequest struct {
+	values []string
+}
+
+// jumpRequest is sent by the jump to time prompt
+type jumpRequest struct {
+	value string
+}
+
+// filterRequest is sent by the filter prompt
+type filterRequest struct {
+	value string
+}
+
+// sourceToggleRequest is sent by the sources menu of a merged view
+type sourceToggleRequest struct {
+	source int
+}
+
+// ContainerLogsWindow shows the logs of a container, or the logs of several containers merged by time
 type ContainerLogsWindow struct {
-	id           string
-	logs_writer  *logsWriter
-	logs_context context.Context
-	logs_cancel  context.CancelFunc
+	ids     []string
+	sources []logSource
+	// whether each source is hidden, for the sources menu
+	hidden_sources []bool
+	logs_writer    *logsWriter
+	logs_context   context.Context
+	logs_cancel    context.CancelFunc
+
+	logs_range    docker.LogsRange
+	stream_cancel context.CancelFunc
+	generation    int
 }
 
-func NewContainerLogsWindow(id string) ContainerLogsWindow {
+func NewContainerLogsWindow(ids []string) ContainerLogsWindow {
 	return ContainerLogsWindow{
-		id:          id,
+		ids:         ids,
 		logs_writer: nil,
 	}
 }
 
 func (w *ContainerLogsWindow) Open(view_ctx context.Context) {
 	w.logs_context, w.logs_cancel = context.WithCancel(view_ctx)
+	w.sources = make([]logSource, len(w.ids))
+	for i, id := range w.ids {
+		w.sources[i] = newLogSource(i, id, strings.TrimPrefix(docker.InspectContainerNoPanic(w.logs_context, id).Name, "/"))
+	}
+	w.hidden_sources = make([]bool, len(w.sources))
+	if len(w.sources) > 1 {
+		bar_window.Info([]rune(fmt.Sprintf("Showing the logs of %d containers, 's' shows or hides each of them", len(w.sources))))
+	}
 	go func() {
-		logs_writer := newLogsWriter(w.logs_context)
+		logs_writer := newLogsWriter(w.logs_context, w.sources)
 		w.logs_writer = &logs_writer
-		go docker.StreamContainerLogs(w.id, &logs_writer, w.logs_context, w.logs_cancel)
+		w.startStream()
 		logs_writer.logPrinter()
 		log.Println("Switcing back...")
 		logs_writer.drawer_semaphore.Acquire(logs_writer.ctx, 1)
@@ -56,7 +96,19 @@ func (w *ContainerLogsWindow) MousePress(tcell.EventMouse) {
 }
 
 func (w *ContainerLogsWindow) HandleEvent(event interface{}, sender window.WindowType) (interface{}, error) {
-	panic("unimplemented HandleEvent for logs window")
+	switch event := event.(type) {
+	case logsRangeRequest:
+		w.handleRangeRequest(event)
+	case jumpRequest:
+		w.handleJumpRequest(event)
+	case filterRequest:
+		w.handleFilterRequest(event)
+	case sourceToggleRequest:
+		w.toggleSource(event.source)
+	default:
+		log.Printf("Got unknown event in logs window %T", event)
+	}
+	return nil, nil
 }
 
 func (w *ContainerLogsWindow) Enable() { w.logs_writer.enable_toggle <- true }
@@ -73,16 +125,15 @@ func (w *ContainerLogsWindow) handleRegularKeyPress(ev *tcell.EventKey) {
 	case tcell.KeyEnter:
 		w.startFollowing()
 	case tcell.KeyUp:
-		log.Printf("view_offset: %d, logs_counter: %d. %d >= (%d - %d)", w.logs_writer.view_offset, w.logs_writer.logs_counter, w.logs_writer.view_offset, w.logs_writer.logs_counter, docker.MaxSavedLogs)
-		if w.logs_writer.view_offset >= (w.logs_writer.logs_counter - docker.MaxSavedLogs) {
-			w.logs_writer.view_offset--
-			w.stopFollowing()
-		}
+		w.scroll(-1)
 	case tcell.KeyDown:
-		if w.logs_writer.view_offset < w.logs_writer.logs_counter-1 {
-			w.logs_writer.view_offset++
-			w.stopFollowing()
-		}
+		w.scroll(1)
+	case tcell.KeyPgUp:
+		w.scroll(-w.pageHeight())
+	case tcell.KeyPgDn:
+		w.scroll(w.pageHeight())
+	case tcell.KeyHome:
+		w.scroll(-w.logs_writer.logs_counter)
 	case tcell.KeyCtrlD:
 		w.logs_cancel()
 	case tcell.KeyRune:
@@ -110,6 +161,41 @@ func (w *ContainerLogsWindow) handleRegularKeyPress(ev *tcell.EventKey) {
 			} else {
 				w.logs_writer.lookup_request <- nil
 			}
+		case 't':
+			w.logs_writer.show_timestamps = !w.logs_writer.show_timestamps
+			w.triggerRedraw()
+		case 'R':
+			w.openRangeForm()
+		case 'j':
+			window.GetScreen().PostEvent(window.NewChangeToPromptEvent("Jump to time (15m, 13:00, 2022-05-01 13:00)", "", window.ContainerLogs,
+				func(value string) interface{} {
+					return jumpRequest{value: value}
+				}))
+		case '&':
+			window.GetScreen().PostEvent(window.NewChangeToPromptEvent("Filter lines (-v PATTERN hides matching lines, level>=warn or key=value compare fields)", "", window.ContainerLogs,
+				func(value string) interface{} {
+					return filterRequest{value: value}
+				}))
+		case 'u':
+			w.changeFilters(popFilter{})
+		case 'U':
+			w.changeFilters(clearFilters{})
+		case 'o':
+			w.changeFilters(toggleStream{stdoutOnly})
+		case 'e':
+			w.changeFilters(toggleStream{stderrOnly})
+		case 's':
+			w.openSourcesMenu()
+		case 'J':
+			w.logs_writer.show_structured = !w.logs_writer.show_structured
+			w.triggerRedraw()
+		case 'd':
+			w.triggerRedraw()
+			w.logs_writer.details_request <- nil
+		case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			if source := int(ev.Rune() - '1'); source < len(w.sources) && len(w.sources) > 1 {
+				w.toggleSource(source)
+			}
 		case 'q':
 			w.logs_cancel()
 		case 'l':
@@ -130,6 +216,20 @@ func (w *ContainerLogsWindow) handleSearchKeyPress(ev *tcell.EventKey) {
 	case tcell.KeyEnter:
 		bar_window.Info([]rune(fmt.Sprintf("Searching for '%s'", w.logs_writer.search_box.Value())))
 		w.logs_writer.is_typing = false
+	case tcell.KeyRune:
+		if ev.Modifiers()&tcell.ModAlt == 0 {
+			w.logs_writer.search_box.HandleKey(ev)
+			break
+		}
+		options := &w.logs_writer.search_options
+		switch ev.Rune() {
+		case 'r':
+			options.is_regex = !options.is_regex
+		case 'c':
+			options.is_case_sensitive = !options.is_case_sensitive
+		case 'w':
+			options.is_whole_word = !options.is_whole_word
+		}
 	default:
 		w.logs_writer.search_box.HandleKey(ev)
 	}
@@ -143,12 +243,137 @@ func (w *ContainerLogsWindow) startFollowing() {
 	w.triggerRedraw()
 }
 
-func (w *ContainerLogsWindow) stopFollowing() {
-	w.logs_writer.is_following = false
-	bar_window.Info([]rune("Stopped following logs"))
+func (w *ContainerLogsWindow) scroll(delta int) {
+	// leave the lookup of search results, which holds the logs printer
+	w.triggerRedraw()
+	w.logs_writer.scroll_request <- delta
+}
+
+func (w *ContainerLogsWindow) changeFilters(change interface{}) {
 	w.triggerRedraw()
+	w.logs_writer.filter_request <- change
+}
+
+func (w *ContainerLogsWindow) handleFilterRequest(request filterRequest) {
+	filter, err := parseFilter(request.value, w.logs_writer.search_options)
+	if err != nil {
+		bar_window.Err([]rune(err.Error()))
+		return
+	}
+	w.changeFilters(addFilter{filter})
+}
+
+func (w *ContainerLogsWindow) pageHeight() int {
+	dimensions := w.logs_writer.dimensions_generator()
+	return window.Height(&dimensions)
 }
 
 func (w *ContainerLogsWindow) triggerRedraw() {
 	w.logs_writer.redraw_request <- nil
 }
+
+// startStream streams the logs of the current time range, replacing the previous stream
+func (w *ContainerLogsWindow) startStream() {
+	if w.stream_cancel != nil {
+		w.stream_cancel()
+		w.generation++
+		w.logs_writer.reset_request <- w.generation
+	}
+	var stream_ctx context.Context
+	stream_ctx, w.stream_cancel = context.WithCancel(w.logs_context)
+	for i, source := range w.sources {
+		stream := streamWriter{writer: w.logs_writer, generation: w.generation, source: i, ctx: stream_ctx}
+		on_error := w.logs_cancel
+		if len(w.sources) > 1 {
+			// the other containers keep streaming
+			name := source.name
+			on_error = func() { bar_window.Err([]rune(fmt.Sprintf("Stopped streaming the logs of %s", name))) }
+		}
+		go docker.StreamContainerLogs(source.id, stream, w.logs_range, stream_ctx, on_error)
+	}
+}
+
+func (w *ContainerLogsWindow) openSourcesMenu() {
+	if len(w.sources) < 2 {
+		return
+	}
+	items := make([]window.MenuItem, len(w.sources))
+	for i, source := range w.sources {
+		mark := "[x]"
+		if w.hidden_sources[i] {
+			mark = "[ ]"
+		}
+		items[i] = window.MenuItem{
+			Label:   fmt.Sprintf("%s %s", mark, source.name),
+			Message: sourceToggleRequest{source: i},
+		}
+	}
+	window.GetScreen().PostEvent(window.NewChangeToMenuEvent("Show logs of", items, 2, 2, window.ContainerLogs))
+}
+
+func (w *ContainerLogsWindow) toggleSource(source int) {
+	w.hidden_sources[source] = !w.hidden_sources[source]
+	w.changeFilters(toggleSource{source: source, name: w.sources[source].name})
+}
+
+func (w *ContainerLogsWindow) openRangeForm() {
+	values := []string{"", ""}
+	if !w.logs_range.Since.IsZero() {
+		values[0] = w.logs_range.Since.Format(docker.LogTimeLayout)
+	}
+	if !w.logs_range.Until.IsZero() {
+		values[1] = w.logs_range.Until.Format(docker.LogTimeLayout)
+	}
+	actions := []window.FormAction{
+		{
+			Key:     tcell.KeyEnter,
+			KeyName: "Enter",
+			Label:   "load",
+			MessageGenerator: func(values []string) interface{} {
+				return logsRangeRequest{values: values}
+			},
+		},
+	}
+	window.GetScreen().PostEvent(window.NewChangeToFormEvent("Logs time range (15m, 2d, 13:00, 2022-05-01 13:00, empty for no limit)",
+		logsRangeLabels, values, actions, window.ContainerLogs))
+}
+
+func (w *ContainerLogsWindow) handleRangeRequest(request logsRangeRequest) {
+	now := time.Now()
+	since, err := docker.ParseLogTime(request.values[0], now)
+	if err != nil {
+		bar_window.Err([]rune(fmt.Sprintf("Invalid since: %s", err)))
+		return
+	}
+	until, err := docker.ParseLogTime(request.values[1], now)
+	if err != nil {
+		bar_window.Err([]rune(fmt.Sprintf("Invalid until: %s", err)))
+		return
+	}
+	if !since.IsZero() && !until.IsZero() && !until.After(since) {
+		bar_window.Err([]rune("Until has to be after since"))
+		return
+	}
+	w.logs_range = docker.LogsRange{Since: since, Until: until}
+	// leave the lookup of search results, which holds the logs printer
+	w.triggerRedraw()
+	w.startStream()
+	if w.logs_range.IsSet() {
+		bar_window.Info([]rune(fmt.Sprintf("Showing logs of %s", w.logs_range)))
+	} else {
+		bar_window.Info([]rune("Showing the latest logs"))
+	}
+}
+
+func (w *ContainerLogsWindow) handleJumpRequest(request jumpRequest) {
+	target, err := docker.ParseLogTime(request.value, time.Now())
+	if err != nil {
+		bar_window.Err([]rune(err.Error()))
+		return
+	}
+	if target.IsZero() {
+		return
+	}
+	w.triggerRedraw()
+	w.logs_writer.jump_request <- target
+}
@@ -1,11 +1,21 @@
 package container_logs_window
 
+import "time"
+
+// LogContainer keeps the logs of a window by their index in the stream, the oldest logs are dropped once it's full
 type LogContainer interface {
-	Put(_log *singleLog, index int)
-	Search(substr string) []int
+	Append(_log *singleLog)
 	Get(index int) *singleLog
+	// First is the index of the oldest log kept, Len the index the next log gets
+	First() int
+	Len() int
+	Search(query *logQuery, from int) []int
+	// Count counts the logs matching the query, stopping at limit
+	Count(query *logQuery, limit int) int
+	Scan(from int, match func(*singleLog) bool) []int
+	Close()
 }
 
 func emptyLog() singleLog {
-	return newLog("", true)
+	return newLog(time.Time{}, "", true)
 }
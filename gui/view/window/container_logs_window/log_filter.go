@@ -0,0 +1,331 @@
+package container_logs_window
+
+import (
+	"dc-top/gui/view/window/bar_window"
+	"dc-top/utils"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type streamFilter uint8
+
+const (
+	allStreams streamFilter = iota
+	stdoutOnly
+	stderrOnly
+)
+
+// logFilter hides the logs the query doesn't match, or the ones it matches when inverted like grep -v
+type logFilter struct {
+	query *logQuery
+	// compares a field of structured logs instead, the query matches the other logs
+	field       *fieldFilter
+	is_inverted bool
+}
+
+func (filter logFilter) matches(_log *singleLog) bool {
+	if filter.field != nil {
+		if structured, ok := parseStructured(_log.content); ok {
+			return filter.field.match(structured)
+		}
+	}
+	return filter.query.Match(_log.content)
+}
+
+func (filter logFilter) String() string {
+	if filter.is_inverted {
+		return "-v " + filter.query.pattern
+	}
+	return filter.query.pattern
+}
+
+const invertedFilterPrefix = "-v "
+
+// parseFilter parses the value of the filter prompt, matching with the current search options
+func parseFilter(value string, options searchOptions) (logFilter, error) {
+	is_inverted := strings.HasPrefix(value, invertedFilterPrefix)
+	pattern := strings.TrimPrefix(value, invertedFilterPrefix)
+	if pattern == "" {
+		return logFilter{}, fmt.Errorf("empty filter")
+	}
+	field, err := parseFieldFilter(pattern, options)
+	if err != nil {
+		return logFilter{}, err
+	}
+	if field != nil {
+		// a log that isn't structured has to contain the comparison as is
+		options = searchOptions{is_case_sensitive: options.is_case_sensitive}
+	}
+	query, err := newLogQuery(pattern, options)
+	if err != nil {
+		return logFilter{}, err
+	}
+	return logFilter{query: query, field: field, is_inverted: is_inverted}, nil
+}
+
+// fieldFilter compares a field of structured logs like `level>=warn` or `request_id=abc`. Levels are compared by
+// severity, numbers by value and anything else as text, or by a regex in regex mode
+type fieldFilter struct {
+	key      string
+	operator string
+	value    string
+	level    logLevel
+	re       *regexp.Regexp
+}
+
+var fieldFilterPattern = regexp.MustCompile(`^([\w.@/-]+)\s*(>=|<=|!=|=|>|<)\s*(\S.*)$`)
+
+// parseFieldFilter returns nil if the pattern isn't a comparison
+func parseFieldFilter(pattern string, options searchOptions) (*fieldFilter, error) {
+	match := fieldFilterPattern.FindStringSubmatch(pattern)
+	if match == nil {
+		return nil, nil
+	}
+	field := &fieldFilter{key: match[1], operator: match[2], value: match[3]}
+	if contains(levelKeys, strings.ToLower(field.key)) {
+		if field.level = parseLevel(field.value); field.level == noLevel {
+			return nil, fmt.Errorf("unknown level '%s', levels are trace, debug, info, warn, error and fatal", field.value)
+		}
+	} else if options.is_regex && (field.operator == "=" || field.operator == "!=") {
+		expression := "^(?:" + field.value + ")$"
+		if !options.is_case_sensitive {
+			expression = "(?i)" + expression
+		}
+		re, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %s", err)
+		}
+		field.re = re
+	}
+	return field, nil
+}
+
+func (field *fieldFilter) match(structured *structuredLog) bool {
+	if field.level != noLevel {
+		return compare(field.operator, int(structured.level)-int(field.level)) && structured.level != noLevel
+	}
+	value, ok := structured.field(field.key)
+	if !ok {
+		return field.operator == "!="
+	}
+	if field.re != nil {
+		return field.re.MatchString(value) == (field.operator == "=")
+	}
+	actual, actual_err := strconv.ParseFloat(value, 64)
+	expected, expected_err := strconv.ParseFloat(field.value, 64)
+	if actual_err == nil && expected_err == nil {
+		switch {
+		case actual < expected:
+			return compare(field.operator, -1)
+		case actual > expected:
+			return compare(field.operator, 1)
+		}
+		return compare(field.operator, 0)
+	}
+	return compare(field.operator, strings.Compare(value, field.value))
+}
+
+// compare applies the operator to the result of comparing two values, negative when the first is smaller
+func compare(operator string, result int) bool {
+	switch operator {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	}
+	return false
+}
+
+// logFilters are stacked, a log is shown only if all of them match it
+type logFilters struct {
+	filters []logFilter
+	stream  streamFilter
+	// the names of the hidden sources of a merged view by their index
+	hidden_sources map[int]string
+}
+
+func (filters *logFilters) isSet() bool {
+	return len(filters.filters) > 0 || filters.stream != allStreams || len(filters.hidden_sources) > 0
+}
+
+func (filters *logFilters) match(_log *singleLog) bool {
+	if (filters.stream == stdoutOnly && !_log.is_stdout) || (filters.stream == stderrOnly && _log.is_stdout) {
+		return false
+	}
+	if _, is_hidden := filters.hidden_sources[_log.source]; is_hidden {
+		return false
+	}
+	for _, filter := range filters.filters {
+		if filter.matches(_log) == filter.is_inverted {
+			return false
+		}
+	}
+	return true
+}
+
+// positiveQuery is a query every shown log matches, to search with the index
+func (filters *logFilters) positiveQuery() *logQuery {
+	for _, filter := range filters.filters {
+		if !filter.is_inverted && filter.field == nil {
+			return filter.query
+		}
+	}
+	return nil
+}
+
+func (filters *logFilters) String() string {
+	descriptions := make([]string, 0, len(filters.filters)+1)
+	for _, filter := range filters.filters {
+		descriptions = append(descriptions, "'"+filter.String()+"'")
+	}
+	switch filters.stream {
+	case stdoutOnly:
+		descriptions = append(descriptions, "stdout only")
+	case stderrOnly:
+		descriptions = append(descriptions, "stderr only")
+	}
+	if len(filters.hidden_sources) > 0 {
+		names := make([]string, 0, len(filters.hidden_sources))
+		for _, name := range filters.hidden_sources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		descriptions = append(descriptions, "not from "+strings.Join(names, ", "))
+	}
+	return strings.Join(descriptions, " & ")
+}
+
+// the changes of the filters sent to the logs printer
+type addFilter struct {
+	filter logFilter
+}
+
+type popFilter struct{}
+
+type clearFilters struct{}
+
+// toggleStream shows only the stream, or both streams if it's already the only one shown
+type toggleStream struct {
+	stream streamFilter
+}
+
+// toggleSource hides or shows the logs of a source of a merged view
+type toggleSource struct {
+	source int
+	name   string
+}
+
+func (writer *logsWriter) changeFilters(change interface{}) {
+	switch change := change.(type) {
+	case addFilter:
+		writer.filters.filters = append(writer.filters.filters, change.filter)
+	case popFilter:
+		if len(writer.filters.filters) == 0 {
+			bar_window.Warn([]rune("No filters to remove"))
+			return
+		}
+		writer.filters.filters = writer.filters.filters[:len(writer.filters.filters)-1]
+	case clearFilters:
+		// hidden sources are toggled separately
+		writer.filters = logFilters{hidden_sources: writer.filters.hidden_sources}
+	case toggleStream:
+		if writer.filters.stream == change.stream {
+			writer.filters.stream = allStreams
+		} else {
+			writer.filters.stream = change.stream
+		}
+	case toggleSource:
+		if writer.filters.hidden_sources == nil {
+			writer.filters.hidden_sources = make(map[int]string)
+		}
+		if _, is_hidden := writer.filters.hidden_sources[change.source]; is_hidden {
+			delete(writer.filters.hidden_sources, change.source)
+		} else {
+			writer.filters.hidden_sources[change.source] = change.name
+		}
+	}
+	writer.refilter()
+	if writer.filters.isSet() {
+		bar_window.Info([]rune(fmt.Sprintf("Showing %d lines matching %s", len(writer.filtered), &writer.filters)))
+	} else {
+		bar_window.Info([]rune("Showing all lines"))
+	}
+	writer.redraw()
+}
+
+// refilter finds the shown logs among all the kept logs
+func (writer *logsWriter) refilter() {
+	writer.filtered = nil
+	if !writer.filters.isSet() {
+		return
+	}
+	if query := writer.filters.positiveQuery(); query != nil {
+		writer.filtered = make([]int, 0)
+		for _, i := range writer.logs_container.Search(query, writer.logs_first) {
+			if writer.filters.match(writer.logs_container.Get(i)) {
+				writer.filtered = append(writer.filtered, i)
+			}
+		}
+	} else {
+		writer.filtered = writer.logs_container.Scan(writer.logs_first, writer.filters.match)
+	}
+}
+
+// previousLog returns the shown log before index, or -1
+func (writer *logsWriter) previousLog(index int) int {
+	if !writer.filters.isSet() {
+		if index-1 < writer.logs_first {
+			return -1
+		}
+		return index - 1
+	}
+	if position := sort.SearchInts(writer.filtered, index) - 1; position >= 0 {
+		return writer.filtered[position]
+	}
+	return -1
+}
+
+// scroll moves the last shown log by delta shown logs, within the kept history
+func (writer *logsWriter) scroll(delta int) {
+	view_offset := writer.view_offset
+	if writer.filters.isSet() {
+		if len(writer.filtered) == 0 {
+			return
+		}
+		position := sort.SearchInts(writer.filtered, view_offset+1) - 1 + delta
+		view_offset = writer.filtered[utils.Max(utils.Min(position, len(writer.filtered)-1), 0)]
+	} else {
+		view_offset = utils.Max(utils.Min(view_offset+delta, writer.logs_counter-1), writer.logs_first)
+	}
+	if view_offset != writer.view_offset {
+		writer.view_offset = view_offset
+		writer.is_following = false
+		bar_window.Info([]rune("Stopped following logs"))
+	}
+	writer.redraw()
+}
+
+// shownOnly keeps the sorted indices of logs that pass the filters
+func (writer *logsWriter) shownOnly(indices []int) []int {
+	if !writer.filters.isSet() {
+		return indices
+	}
+	shown := make([]int, 0, len(indices))
+	for _, i := range indices {
+		if position := sort.SearchInts(writer.filtered, i); position < len(writer.filtered) && writer.filtered[position] == i {
+			shown = append(shown, i)
+		}
+	}
+	return shown
+}
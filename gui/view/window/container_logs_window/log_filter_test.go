@@ -0,0 +1,35 @@
+package container_logs_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStackedLogFilters(t *testing.T) {
+	filters := logFilters{}
+	for _, value := range []string{"request", "-v healthcheck"} {
+		filter, err := parseFilter(value, searchOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		filters.filters = append(filters.filters, filter)
+	}
+	cases := map[string]bool{
+		"GET request /users":       true,
+		"GET request /healthcheck": false,
+		"started":                  false,
+	}
+	for content, expected := range cases {
+		_log := newLog(time.Time{}, content, true)
+		if filters.match(&_log) != expected {
+			t.Errorf("%q: expected %t", content, expected)
+		}
+	}
+	filters.stream = stderrOnly
+	if _log := newLog(time.Time{}, "GET request /users", true); filters.match(&_log) {
+		t.Error("expected stdout to be hidden")
+	}
+	if filters.String() != "'request' & '-v healthcheck' & stderr only" {
+		t.Errorf("unexpected description %q", filters.String())
+	}
+}
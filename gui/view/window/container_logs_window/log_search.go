@@ -0,0 +1,228 @@
+package container_logs_window
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+// searchOptions are toggled from the search prompt
+type searchOptions struct {
+	is_regex          bool
+	is_case_sensitive bool
+	is_whole_word     bool
+}
+
+func (options searchOptions) String() string {
+	flags := []string{"regex", "case", "word"}
+	for i, is_set := range []bool{options.is_regex, options.is_case_sensitive, options.is_whole_word} {
+		if is_set {
+			flags[i] = "[" + strings.ToUpper(flags[i]) + "]"
+		}
+	}
+	return strings.Join(flags, " ")
+}
+
+// logQuery matches logs by a substring or a regex, all of them are compiled to a regex to find the matches to
+// highlight, plain substrings are matched without it
+type logQuery struct {
+	pattern string
+	options searchOptions
+	re      *regexp.Regexp
+	// strings every match contains, segments whose index lacks one of them are skipped
+	literals []string
+}
+
+func newLogQuery(pattern string, options searchOptions) (*logQuery, error) {
+	expression := pattern
+	if !options.is_regex {
+		expression = regexp.QuoteMeta(pattern)
+	}
+	if options.is_whole_word {
+		expression = `\b(?:` + expression + `)\b`
+	}
+	if !options.is_case_sensitive {
+		expression = `(?i)` + expression
+	}
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %s", strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+	}
+	query := &logQuery{pattern: pattern, options: options, re: re, literals: []string{pattern}}
+	if options.is_regex {
+		parsed, err := syntax.Parse(pattern, syntax.Perl)
+		if err != nil {
+			return nil, err
+		}
+		query.literals = requiredLiterals(parsed.Simplify())
+	}
+	return query, nil
+}
+
+// requiredLiterals returns literal parts of the regex that every match contains
+func requiredLiterals(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return []string{string(re.Rune)}
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpConcat:
+		var literals []string
+		for _, sub := range re.Sub {
+			literals = append(literals, requiredLiterals(sub)...)
+		}
+		return literals
+	}
+	return nil
+}
+
+func (query *logQuery) Match(content string) bool {
+	if query.options.is_regex || query.options.is_whole_word {
+		return query.re.MatchString(content)
+	}
+	if query.options.is_case_sensitive {
+		return strings.Contains(content, query.pattern)
+	}
+	if isASCII(query.pattern) {
+		return containsFoldASCII(content, query.pattern)
+	}
+	return query.re.MatchString(content)
+}
+
+func (query *logQuery) Matches(content string) [][]int {
+	return query.re.FindAllStringIndex(content, -1)
+}
+
+func (query *logQuery) equals(other *logQuery) bool {
+	return query.pattern == other.pattern && query.options == other.options
+}
+
+// narrows is true when every log the query matches is matched by previous, like when another letter is typed
+func (query *logQuery) narrows(previous *logQuery) bool {
+	if query.options != previous.options || query.options.is_regex || query.options.is_whole_word {
+		return false
+	}
+	if query.options.is_case_sensitive {
+		return strings.Contains(query.pattern, previous.pattern)
+	}
+	return containsFoldASCII(query.pattern, previous.pattern) && isASCII(previous.pattern)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+func lowerASCII(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+// containsFoldASCII is strings.Contains ignoring the case of an ASCII substr, without lowercasing s
+func containsFoldASCII(s, substr string) bool {
+	if substr == "" {
+		return true
+	}
+	first := lowerASCII(substr[0])
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if lowerASCII(s[i]) != first {
+			continue
+		}
+		j := 1
+		for j < len(substr) && lowerASCII(s[i+j]) == lowerASCII(substr[j]) {
+			j++
+		}
+		if j == len(substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// the index of a segment is a bitset of the trigrams in its logs. Bytes are folded to 64 classes: letters ignoring
+// case, digits, common punctuation and everything else, so a trigram is 18 bits
+const trigramBits = 1 << 18
+
+var byteClasses [256]uint32
+
+func init() {
+	for i := range byteClasses {
+		byteClasses[i] = 63
+	}
+	class := uint32(0)
+	for b := 'a'; b <= 'z'; b++ {
+		byteClasses[b], byteClasses[b-'a'+'A'] = class, class
+		class++
+	}
+	for _, b := range []byte("0123456789 -_.:/=\"'{}[](),;@#%&*+<>?!|\\$") {
+		if class == 63 {
+			break
+		}
+		byteClasses[b] = class
+		class++
+	}
+}
+
+type trigramIndex []uint64
+
+func newTrigramIndex() trigramIndex {
+	return make(trigramIndex, trigramBits/64)
+}
+
+func trigram(s string, i int) uint32 {
+	return byteClasses[s[i]]<<12 | byteClasses[s[i+1]]<<6 | byteClasses[s[i+2]]
+}
+
+func (index trigramIndex) add(content string) {
+	for i := 0; i+2 < len(content); i++ {
+		t := trigram(content, i)
+		index[t/64] |= 1 << (t % 64)
+	}
+}
+
+// mayContain is false when no log in the segment can contain all the literals
+func (index trigramIndex) mayContain(literals []string) bool {
+	for _, literal := range literals {
+		for i := 0; i+2 < len(literal); i++ {
+			if t := trigram(literal, i); index[t/64]&(1<<(t%64)) == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// searchResults are the logs matched by the last query, a repeated query only searches the logs written since and
+// a narrower one only searches these results
+type searchResults struct {
+	query          *logQuery
+	indices        []int
+	searched_until int
+}
+
+func (writer *logsWriter) search(query *logQuery) []int {
+	container := writer.logs_container
+	previous := writer.search_results
+	var indices []int
+	searched_until := container.First()
+	if previous != nil && (query.equals(previous.query) || query.narrows(previous.query)) {
+		indices = make([]int, 0, len(previous.indices))
+		for _, i := range previous.indices {
+			if i >= container.First() && (query.equals(previous.query) || query.Match(container.Get(i).content)) {
+				indices = append(indices, i)
+			}
+		}
+		searched_until = previous.searched_until
+	}
+	indices = append(indices, container.Search(query, searched_until)...)
+	writer.search_results = &searchResults{query: query, indices: indices, searched_until: container.Len()}
+	return indices
+}
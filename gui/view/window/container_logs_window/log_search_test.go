@@ -0,0 +1,46 @@
+package container_logs_window
+
+import "testing"
+
+func TestLogQueryModes(t *testing.T) {
+	cases := []struct {
+		pattern string
+		options searchOptions
+		content string
+		matches bool
+	}{
+		{"error", searchOptions{}, "an ERROR occurred", true},
+		{"error", searchOptions{is_case_sensitive: true}, "an ERROR occurred", false},
+		{"err", searchOptions{is_whole_word: true}, "an error occurred", false},
+		{"error", searchOptions{is_whole_word: true}, "an error: occurred", true},
+		{`took \d+ms`, searchOptions{is_regex: true}, "request took 120ms", true},
+		{`took \d+ms`, searchOptions{}, "request took 120ms", false},
+		{"ünïcode", searchOptions{}, "ÜNÏCODE line", true},
+	}
+	for _, c := range cases {
+		query, err := newLogQuery(c.pattern, c.options)
+		if err != nil {
+			t.Fatalf("%q: %s", c.pattern, err)
+		}
+		if query.Match(c.content) != c.matches {
+			t.Errorf("%q %+v on %q: expected %t", c.pattern, c.options, c.content, c.matches)
+		}
+	}
+	if _, err := newLogQuery("(", searchOptions{is_regex: true}); err == nil {
+		t.Error("expected an invalid regex to fail")
+	}
+}
+
+func TestTrigramIndexSkipsSegments(t *testing.T) {
+	index := newTrigramIndex()
+	index.add("GET /api/users 200")
+	for literals, expected := range map[string]bool{"api/USERS": true, "users 2": true, "orders": false, "xy": true} {
+		if index.mayContain([]string{literals}) != expected {
+			t.Errorf("%q: expected %t", literals, expected)
+		}
+	}
+	query, _ := newLogQuery(`user(s|names) \d+`, searchOptions{is_regex: true})
+	if len(query.literals) != 2 || query.literals[0] != "user" || query.literals[1] != " " {
+		t.Errorf("expected the regex to require 'user', got %q", query.literals)
+	}
+}
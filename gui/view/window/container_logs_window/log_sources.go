@@ -0,0 +1,155 @@
+package container_logs_window
+
+import (
+	"container/heap"
+	docker "dc-top/docker"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// logSource is one of the containers whose logs are shown, each has its own color like in docker compose logs
+type logSource struct {
+	id    string
+	name  string
+	style tcell.Style
+}
+
+var sourceColors = []tcell.Color{
+	tcell.ColorDarkCyan,
+	tcell.ColorYellow,
+	tcell.ColorGreen,
+	tcell.ColorFuchsia,
+	tcell.ColorBlue,
+	tcell.ColorOrange,
+	tcell.ColorTeal,
+	tcell.ColorOlive,
+	tcell.ColorPurple,
+	tcell.ColorAqua,
+}
+
+func newLogSource(index int, id string, name string) logSource {
+	if name == "" {
+		name = id
+	}
+	return logSource{
+		id:    id,
+		name:  name,
+		style: tcell.StyleDefault.Foreground(sourceColors[index%len(sourceColors)]),
+	}
+}
+
+// the logs of a source wait this long for the other sources to catch up before they're shown
+const (
+	mergeDelay = 500 * time.Millisecond
+	// the sources that sent nothing yet are waited for when the streams start
+	mergeStartDelay = 2 * time.Second
+)
+
+type pendingLog struct {
+	line   docker.LogLine
+	source int
+}
+
+// logMerger interleaves the logs of several streams by their timestamps. Every stream is in order, so a log can be
+// shown once every stream that's still active wrote a newer one, streams that went quiet aren't waited for
+type logMerger struct {
+	// the logs of each source that weren't shown yet, in time order
+	queues [][]pendingLog
+	// the time of the newest log of each source and when it arrived
+	newest  []time.Time
+	arrived []time.Time
+	started time.Time
+}
+
+func newLogMerger(sources int, now time.Time) *logMerger {
+	return &logMerger{
+		queues:  make([][]pendingLog, sources),
+		newest:  make([]time.Time, sources),
+		arrived: make([]time.Time, sources),
+		started: now,
+	}
+}
+
+func (merger *logMerger) add(source int, lines []docker.LogLine, now time.Time) {
+	for _, line := range lines {
+		// a log without a timestamp stays after the one before it
+		if line.Time.IsZero() {
+			line.Time = merger.newest[source]
+		}
+		merger.newest[source] = line.Time
+		merger.queues[source] = append(merger.queues[source], pendingLog{line, source})
+	}
+	merger.arrived[source] = now
+}
+
+// ready removes and returns the pending logs no active source can write an older log than
+func (merger *logMerger) ready(now time.Time) []pendingLog {
+	var threshold time.Time
+	is_limited := false
+	for source, arrived := range merger.arrived {
+		is_active := now.Sub(arrived) < mergeDelay
+		if arrived.IsZero() {
+			is_active = now.Sub(merger.started) < mergeStartDelay
+		}
+		if is_active && (!is_limited || merger.newest[source].Before(threshold)) {
+			threshold, is_limited = merger.newest[source], true
+		}
+	}
+	heads := &queueHeads{merger: merger}
+	for source, queue := range merger.queues {
+		if len(queue) > 0 {
+			heads.sources = append(heads.sources, source)
+		}
+	}
+	heap.Init(heads)
+	ready := make([]pendingLog, 0)
+	for heads.Len() > 0 {
+		source := heads.sources[0]
+		next := merger.queues[source][0]
+		if is_limited && next.line.Time.After(threshold) {
+			break
+		}
+		ready = append(ready, next)
+		merger.queues[source] = merger.queues[source][1:]
+		if len(merger.queues[source]) == 0 {
+			// drops the queue's array, which can be big after the initial tail
+			merger.queues[source] = nil
+			heap.Pop(heads)
+		} else {
+			heap.Fix(heads, 0)
+		}
+	}
+	return ready
+}
+
+// queueHeads is a heap of the sources with pending logs, by the time of their oldest pending log
+type queueHeads struct {
+	merger  *logMerger
+	sources []int
+}
+
+func (heads *queueHeads) Len() int { return len(heads.sources) }
+
+func (heads *queueHeads) Less(i, j int) bool {
+	time_i := heads.merger.queues[heads.sources[i]][0].line.Time
+	time_j := heads.merger.queues[heads.sources[j]][0].line.Time
+	if time_i.Equal(time_j) {
+		return heads.sources[i] < heads.sources[j]
+	}
+	return time_i.Before(time_j)
+}
+
+func (heads *queueHeads) Swap(i, j int) {
+	heads.sources[i], heads.sources[j] = heads.sources[j], heads.sources[i]
+}
+
+func (heads *queueHeads) Push(source interface{}) {
+	heads.sources = append(heads.sources, source.(int))
+}
+
+func (heads *queueHeads) Pop() interface{} {
+	last := heads.sources[len(heads.sources)-1]
+	heads.sources = heads.sources[:len(heads.sources)-1]
+	return last
+}
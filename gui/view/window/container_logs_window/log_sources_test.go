@@ -0,0 +1,35 @@
+package container_logs_window
+
+import (
+	docker "dc-top/docker"
+	"testing"
+	"time"
+)
+
+func TestLogMergerInterleavesByTime(t *testing.T) {
+	start := time.Date(2022, 5, 1, 13, 0, 0, 0, time.UTC)
+	at := func(seconds int, text string) docker.LogLine {
+		return docker.LogLine{Time: start.Add(time.Duration(seconds) * time.Second), Text: text}
+	}
+	now := start.Add(time.Hour)
+	merger := newLogMerger(2, now)
+	merger.add(0, []docker.LogLine{at(1, "a1"), at(3, "a3"), at(5, "a5")}, now)
+	if ready := merger.ready(now); len(ready) != 0 {
+		t.Fatalf("expected the logs to wait for the other source to start, got %d", len(ready))
+	}
+	merger.add(1, []docker.LogLine{at(2, "b2"), at(4, "b4")}, now)
+	expected := []string{"a1", "b2", "a3", "b4"}
+	ready := merger.ready(now)
+	if len(ready) != len(expected) {
+		t.Fatalf("expected %d ready logs, got %d", len(expected), len(ready))
+	}
+	for i, text := range expected {
+		if ready[i].line.Text != text {
+			t.Errorf("log %d: expected %s, got %s", i, text, ready[i].line.Text)
+		}
+	}
+	// a5 is shown once the second source goes quiet
+	if ready := merger.ready(now.Add(mergeDelay)); len(ready) != 1 || ready[0].line.Text != "a5" || ready[0].source != 0 {
+		t.Errorf("expected a5 to be ready, got %v", ready)
+	}
+}
@@ -2,6 +2,7 @@ package container_logs_window
 
 import (
 	"context"
+	"dc-top/config"
 	docker "dc-top/docker"
 	"dc-top/gui/elements"
 	"dc-top/gui/view/window"
@@ -9,11 +10,22 @@ import (
 	"dc-top/utils"
 	"fmt"
 	"log"
+	"sort"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"golang.org/x/sync/semaphore"
 )
 
+const (
+	timestampLayout = "2006-01-02 15:04:05.000"
+	// how often the logs of merged streams are checked for ones that are ready
+	mergeInterval = 100 * time.Millisecond
+	// the live match count is only updated once typing pauses, and stops counting at searchCountLimit
+	searchCountDelay = 300 * time.Millisecond
+	searchCountLimit = 1000
+)
+
 type logsWriter struct {
 	ctx              context.Context
 	drawer_semaphore *semaphore.Weighted
@@ -22,43 +34,92 @@ type logsWriter struct {
 	is_typing            bool
 	is_enabled           bool
 	is_looking           bool
+	show_timestamps      bool
+	show_structured      bool
 	dimensions_generator func() window.Dimensions
 
+	// the containers whose logs are shown, their logs are merged by time when there are several
+	sources           []logSource
+	source_name_width int
+	merger            *logMerger
+
 	logs_container LogContainer
-	logs_offset    int
-	logs_counter   int
-	view_offset    int
-	lines          []elements.StringStyler
+	// the indices of the oldest kept log and of the next log
+	logs_first   int
+	logs_counter int
+	view_offset  int
+	lines        []elements.StringStyler
+	filters      logFilters
+	// the sorted indices of the logs that pass the filters, while there are filters
+	filtered        []int
+	filter_request  chan interface{}
+	scroll_request  chan int
+	details_request chan interface{}
 
 	search_box     elements.TextBox
-	lookup_request chan interface{}
-	next_search    chan interface{}
-	prev_search    chan interface{}
+	search_options searchOptions
+	search_results *searchResults
+	// the live match count shown while typing
+	search_status string
+	// fires when the match count should be updated, nil while no update is pending
+	search_count_timer <-chan time.Time
+	lookup_request     chan interface{}
+	next_search        chan interface{}
+	prev_search        chan interface{}
 
 	redraw_request chan interface{}
-	write_queue    chan []string
+	write_queue    chan logBatch
 	enable_toggle  chan bool
+	// lines of streams older than generation are dropped
+	generation    int
+	reset_request chan int
+	jump_request  chan time.Time
+}
+
+// logBatch is a batch of lines of a source's stream `generation`, the streams are replaced when the time range
+// changes
+type logBatch struct {
+	generation int
+	source     int
+	lines      []docker.LogLine
+}
+
+// streamWriter passes the lines of one stream to the logs writer
+type streamWriter struct {
+	writer     *logsWriter
+	generation int
+	source     int
+	ctx        context.Context
+}
+
+func (stream streamWriter) WriteLines(lines []docker.LogLine) {
+	select {
+	case stream.writer.write_queue <- logBatch{stream.generation, stream.source, lines}:
+	case <-stream.ctx.Done():
+	}
 }
 
-func newLogsWriter(ctx context.Context) logsWriter {
+func newLogsWriter(ctx context.Context, sources []logSource) logsWriter {
 	_, y1, _, y2 := window.LogsWindowSize()
 	height := y2 - y1
-	logs_container := NewArrStringSearcher(docker.MaxSavedLogs)
 	new_writer := logsWriter{
 		ctx:              ctx,
 		drawer_semaphore: semaphore.NewWeighted(1),
 
-		is_following: true,
-		is_typing:    false,
-		is_enabled:   true,
-		is_looking:   false,
+		sources: sources,
+
+		is_following:    true,
+		show_structured: true,
+		is_typing:       false,
+		is_enabled:      true,
+		is_looking:      false,
 		dimensions_generator: func() window.Dimensions {
 			x1, y1, x2, y2 := window.LogsWindowSize()
 			return window.NewDimensions(x1, y1, x2, y2, false)
 		},
 
-		logs_container: &logs_container,
-		logs_offset:    0,
+		logs_container: NewSegmentedLogContainer(config.MaxLogLines(), config.LogsMemoryLimit()),
+		logs_first:     0,
 		logs_counter:   0,
 		view_offset:    0,
 		lines:          make([]elements.StringStyler, height),
@@ -73,53 +134,93 @@ func newLogsWriter(ctx context.Context) logsWriter {
 		next_search:    make(chan interface{}),
 		prev_search:    make(chan interface{}),
 
-		redraw_request: make(chan interface{}),
-		write_queue:    make(chan []string),
-		enable_toggle:  make(chan bool),
+		redraw_request:  make(chan interface{}),
+		write_queue:     make(chan logBatch),
+		enable_toggle:   make(chan bool),
+		reset_request:   make(chan int),
+		filter_request:  make(chan interface{}),
+		scroll_request:  make(chan int),
+		details_request: make(chan interface{}),
+		jump_request:    make(chan time.Time),
 	}
-	return new_writer
-}
-
-func (writer *logsWriter) Write(logs_batch []byte) (int, error) {
-	var nl_index int
-	logs := make([]string, 0)
-	for offset := 0; nl_index != -1 && offset < len(logs_batch); offset += (nl_index + 1) {
-		nl_index = utils.FindByte('\n', []byte(logs_batch[offset:]))
-		var log_line string
-		if nl_index != -1 {
-			log_line = string(logs_batch[offset : offset+nl_index])
-		} else {
-			log_line = string(logs_batch[offset:])
+	if len(sources) > 1 {
+		new_writer.merger = newLogMerger(len(sources), time.Now())
+		for _, source := range sources {
+			new_writer.source_name_width = utils.Max(new_writer.source_name_width, len(source.name))
 		}
-		logs = append(logs, log_line)
 	}
-	writer.write_queue <- logs
-	return len(logs_batch), nil
+	return new_writer
 }
 
 func (writer *logsWriter) logPrinter() {
 	writer.drawer_semaphore.Acquire(writer.ctx, 1)
 	defer writer.drawer_semaphore.Release(1)
+	defer func() { writer.logs_container.Close() }()
+	var merge_tick <-chan time.Time
+	if writer.merger != nil {
+		ticker := time.NewTicker(mergeInterval)
+		defer ticker.Stop()
+		merge_tick = ticker.C
+	}
 	writer.redraw()
 	for {
 		select {
 		case <-writer.lookup_request:
-			if writer.search_box.Value() == "" {
+			query, err := writer.query()
+			if err != nil {
+				bar_window.Err([]rune(err.Error()))
+				break
+			} else if query == nil {
 				break
 			}
 			writer.is_following = false
-			indices := writer.logs_container.Search(writer.search_box.Value())
-			bar_window.Info([]rune(fmt.Sprintf("Found %d results for %s", len(indices), writer.search_box.Value())))
+			indices := writer.shownOnly(writer.search(query))
+			bar_window.Info([]rune(fmt.Sprintf("Found %d results for %s", len(indices), query.pattern)))
 			if len(indices) > 0 {
 				writer.handleLookup(indices)
 			}
 			writer.redraw()
-		case logs := <-writer.write_queue:
-			writer.writeLogs(logs)
+		case batch := <-writer.write_queue:
+			if batch.generation != writer.generation {
+				break
+			}
+			if writer.merger != nil {
+				writer.merger.add(batch.source, batch.lines, time.Now())
+				writer.writeLogs(writer.merger.ready(time.Now()))
+			} else {
+				logs := make([]pendingLog, len(batch.lines))
+				for i, line := range batch.lines {
+					logs[i] = pendingLog{line: line, source: batch.source}
+				}
+				writer.writeLogs(logs)
+			}
+		case <-merge_tick:
+			writer.writeLogs(writer.merger.ready(time.Now()))
+		case generation := <-writer.reset_request:
+			writer.reset(generation)
+		case target := <-writer.jump_request:
+			writer.jumpTo(target)
+		case change := <-writer.filter_request:
+			writer.changeFilters(change)
+		case delta := <-writer.scroll_request:
+			writer.scroll(delta)
+		case <-writer.details_request:
+			writer.showDetails()
 		case <-writer.redraw_request:
+			if writer.is_typing {
+				writer.search_count_timer = time.After(searchCountDelay)
+			}
 			if writer.is_enabled {
 				writer.redraw()
 			}
+		case <-writer.search_count_timer:
+			writer.search_count_timer = nil
+			if writer.is_typing {
+				writer.updateSearchStatus()
+				if writer.is_enabled {
+					writer.redraw()
+				}
+			}
 		case is_enabled := <-writer.enable_toggle:
 			writer.is_enabled = is_enabled
 			if writer.is_enabled {
@@ -168,34 +269,100 @@ func (writer *logsWriter) handleLookup(result_indices []int) {
 	}
 }
 
-func (writer *logsWriter) writeLogs(logs []string) {
+func (writer *logsWriter) writeLogs(logs []pendingLog) {
 	for _, l := range logs {
-		writer.saveLog(l)
+		writer.saveLog(l.line, l.source)
 	}
-	if writer.is_following && writer.is_enabled {
+	if len(logs) > 0 && writer.is_following && writer.is_enabled {
 		writer.redraw()
 	}
 }
 
-func (writer *logsWriter) saveLog(_log string) {
-	const metadata_len = 8
-	var new_log singleLog
-	if len(_log) > metadata_len {
-		metadata := _log[:metadata_len]
-		log_line_text := _log[metadata_len:]
-		is_stdout := (metadata[0] == 1)
-		new_log = newLog(log_line_text, is_stdout)
-	} else {
-		new_log = newLog("", true)
+func (writer *logsWriter) saveLog(_log docker.LogLine, source int) {
+	new_log := newLog(_log.Time, _log.Text, !_log.IsStderr)
+	new_log.source = source
+	writer.logs_container.Append(&new_log)
+	if first := writer.logs_container.First(); first != writer.logs_first {
+		writer.logs_first = first
+		writer.filtered = writer.filtered[sort.SearchInts(writer.filtered, first):]
+	}
+	if writer.filters.isSet() && writer.filters.match(&new_log) {
+		writer.filtered = append(writer.filtered, writer.logs_counter)
 	}
-	writer.logs_container.Put(&new_log, writer.logs_offset)
-	writer.logs_offset = (writer.logs_offset + 1) % docker.MaxSavedLogs
-	writer.logs_counter++
+	writer.logs_counter = writer.logs_container.Len()
 	if writer.is_following {
 		writer.view_offset = writer.logs_counter - 1
 	}
 }
 
+// reset drops the saved logs before the stream of a new time range starts
+func (writer *logsWriter) reset(generation int) {
+	writer.logs_container.Close()
+	writer.logs_container = NewSegmentedLogContainer(config.MaxLogLines(), config.LogsMemoryLimit())
+	writer.generation = generation
+	if writer.merger != nil {
+		writer.merger = newLogMerger(len(writer.sources), time.Now())
+	}
+	writer.search_results = nil
+	writer.filtered = nil
+	writer.logs_first = 0
+	writer.logs_counter = 0
+	writer.view_offset = 0
+	writer.is_following = true
+	writer.redraw()
+}
+
+// jumpTo shows the first saved log written at or after target at the top of the window
+func (writer *logsWriter) jumpTo(target time.Time) {
+	// logs are in time order, except the ones without a timestamp
+	oldest := writer.logs_first
+	found := oldest + sort.Search(writer.logs_counter-oldest, func(i int) bool {
+		log_time := writer.logs_container.Get(oldest + i).time
+		return !log_time.IsZero() && !log_time.Before(target)
+	})
+	if found == writer.logs_counter {
+		bar_window.Warn([]rune(fmt.Sprintf("No logs after %s", target.Local().Format(timestampLayout))))
+		return
+	}
+	if first_time := writer.logs_container.Get(found).time; found == oldest && first_time.After(target) {
+		bar_window.Warn([]rune(fmt.Sprintf("Logs before %s aren't loaded, press 'R' to load a time range", first_time.Local().Format(timestampLayout))))
+	} else {
+		bar_window.Info([]rune(fmt.Sprintf("Jumped to %s", target.Local().Format(timestampLayout))))
+	}
+	dimensions := writer.dimensions_generator()
+	writer.is_following = false
+	writer.view_offset = utils.Min(found+window.Height(&dimensions)-1, writer.logs_counter-1)
+	if writer.filters.isSet() && len(writer.filtered) > 0 {
+		position := sort.SearchInts(writer.filtered, found)
+		writer.view_offset = writer.filtered[utils.Min(position+window.Height(&dimensions)-1, len(writer.filtered)-1)]
+	}
+	writer.redraw()
+}
+
+// query is nil when nothing is searched
+func (writer *logsWriter) query() (*logQuery, error) {
+	if writer.search_box.Value() == "" {
+		return nil, nil
+	}
+	return newLogQuery(writer.search_box.Value(), writer.search_options)
+}
+
+func (writer *logsWriter) updateSearchStatus() {
+	query, err := writer.query()
+	if err != nil {
+		writer.search_status = err.Error()
+	} else if query == nil {
+		writer.search_status = ""
+	} else {
+		count := writer.logs_container.Count(query, searchCountLimit)
+		if count == searchCountLimit {
+			writer.search_status = fmt.Sprintf("%d+ matches", count)
+		} else {
+			writer.search_status = fmt.Sprintf("%d matches", count)
+		}
+	}
+}
+
 func (writer *logsWriter) redraw() {
 	writer.updateLines()
 	writer.showLines()
@@ -206,18 +373,23 @@ func (writer *logsWriter) updateLines() {
 	width := window.Width(&dimensions)
 	height := window.Height(&dimensions)
 	writer.lines = make([]elements.StringStyler, height)
-	log_i := writer.view_offset % docker.MaxSavedLogs
-	if log_i < 0 {
-		log_i = docker.MaxSavedLogs + log_i
-	}
-	for line_i := height - 1; line_i >= 0; {
-		log_line_text := writer.logs_container.Get(log_i).content
-		log_line_style := tcell.StyleDefault
-		if !writer.logs_container.Get(log_i).is_stdout {
-			log_line_style = log_line_style.Foreground(tcell.ColorRed)
+	query, _ := writer.query()
+	log_i := writer.previousLog(writer.view_offset + 1)
+	for line_i := height - 1; line_i >= 0 && log_i >= 0; {
+		_log := writer.logs_container.Get(log_i)
+		log_line_text, log_with_highlights := writer.renderLog(_log, query)
+		if log_time := _log.time; writer.show_timestamps && !log_time.IsZero() {
+			timestamp := log_time.Local().Format(timestampLayout) + " "
+			log_with_highlights = elements.TextDrawer(timestamp, tcell.StyleDefault.Foreground(tcell.ColorDarkCyan)).Concat(len(timestamp), log_with_highlights)
+			log_line_text = timestamp + log_line_text
+		}
+		if len(writer.sources) > 1 {
+			source := writer.sources[_log.source]
+			prefix := fmt.Sprintf("%-*s | ", writer.source_name_width, source.name)
+			log_with_highlights = elements.TextDrawer(prefix, source.style).Concat(len(prefix), log_with_highlights)
+			log_line_text = prefix + log_line_text
 		}
 		num_partitions := 1 + (len(log_line_text)-1)/width
-		log_with_highlights := elements.HighlightDrawer(log_line_text, writer.search_box.Value(), log_line_style)
 		for j := 0; j < num_partitions; j++ {
 			writer.lines[line_i] = elements.Suffix(log_with_highlights, (num_partitions-j-1)*width)
 			line_i--
@@ -225,17 +397,54 @@ func (writer *logsWriter) updateLines() {
 				break
 			}
 		}
-		log_i--
-		if log_i < 0 {
-			log_i = docker.MaxSavedLogs - 1
+		log_i = writer.previousLog(log_i)
+	}
+}
+
+// renderLog returns the text of the log and its drawer, structured logs are rendered by their fields
+func (writer *logsWriter) renderLog(_log *singleLog, query *logQuery) (string, elements.StringStyler) {
+	if writer.show_structured {
+		if structured, ok := parseStructured(_log.content); ok {
+			return renderStructured(structured, query)
 		}
 	}
+	style := tcell.StyleDefault
+	if !_log.is_stdout {
+		style = style.Foreground(tcell.ColorRed)
+	}
+	if query != nil {
+		return _log.content, elements.MatchesHighlightDrawer(_log.content, query.Matches(_log.content), style)
+	}
+	return _log.content, elements.TextDrawer(_log.content, style)
+}
+
+// showDetails opens the last shown log in a pager, structured logs with one field per line
+func (writer *logsWriter) showDetails() {
+	log_i := writer.previousLog(writer.view_offset + 1)
+	if log_i < 0 {
+		return
+	}
+	_log := writer.logs_container.Get(log_i)
+	contents := []byte(_log.content)
+	if structured, ok := parseStructured(_log.content); ok {
+		contents = structured.details(_log.content)
+	}
+	title := "Log details"
+	if !_log.time.IsZero() {
+		title = fmt.Sprintf("Log of %s", _log.time.Local().Format(timestampLayout))
+	}
+	if len(writer.sources) > 1 {
+		title += " from " + writer.sources[_log.source].name
+	}
+	window.GetScreen().PostEvent(window.NewChangeToPagerEvent(title, contents, ""))
 }
 
 func (writer *logsWriter) showLines() {
 	dimensions := writer.dimensions_generator()
 	height := window.Height(&dimensions)
-	search_box := writer.search_box.Style()
+	search_status := fmt.Sprintf("  %s  %s (Alt+r/c/w)", writer.search_status, writer.search_options)
+	search_box := writer.search_box.Style().Concat(2+len(writer.search_box.Value())+1,
+		elements.TextDrawer(search_status, tcell.StyleDefault.Foreground(tcell.ColorYellow)))
 	not_following_message := elements.TextDrawer("Currently not following logs. Press 'f' to start following.", tcell.StyleDefault.Background(tcell.ColorGreen).Bold(true))
 	log_drawer := func(i int, j int) (rune, tcell.Style) {
 		if j == 0 {
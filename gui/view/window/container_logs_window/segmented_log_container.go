@@ -0,0 +1,324 @@
+package container_logs_window
+
+import (
+	"bufio"
+	"dc-top/utils"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	segmentLines = 16 * 1024
+	// a rough size of a log in memory besides its content
+	logOverhead = 64
+	// spilled segments kept loaded for scrolling back and forth
+	loadedSegments = 4
+)
+
+type logSegment struct {
+	first int
+	// nil while the segment is spilled to path
+	logs  []*singleLog
+	count int
+	size  int64
+	path  string
+	// stays in memory when the segment is spilled
+	index trigramIndex
+}
+
+// SegmentedLogContainer keeps up to max_lines logs in segments, when the segments in memory grow over memory_limit
+// bytes the oldest ones are spilled to files in a temp directory and loaded back when they're shown or searched
+type SegmentedLogContainer struct {
+	max_lines    int
+	memory_limit int64
+	memory_size  int64
+
+	segments []*logSegment
+	// the spilled segments that are loaded, most recently used last
+	loaded   []*logSegment
+	temp_dir string
+	// the number of segments ever spilled, to name their files
+	spilled int
+}
+
+func NewSegmentedLogContainer(max_lines int, memory_limit int64) *SegmentedLogContainer {
+	return &SegmentedLogContainer{
+		max_lines:    max_lines,
+		memory_limit: memory_limit,
+	}
+}
+
+func (container *SegmentedLogContainer) First() int {
+	if len(container.segments) == 0 {
+		return 0
+	}
+	return container.segments[0].first
+}
+
+func (container *SegmentedLogContainer) Len() int {
+	if len(container.segments) == 0 {
+		return 0
+	}
+	last := container.segments[len(container.segments)-1]
+	return last.first + last.count
+}
+
+func (container *SegmentedLogContainer) Append(_log *singleLog) {
+	if len(container.segments) == 0 || container.segments[len(container.segments)-1].count == segmentLines {
+		container.segments = append(container.segments, &logSegment{
+			first: container.Len(),
+			logs:  make([]*singleLog, 0, segmentLines),
+			index: newTrigramIndex(),
+		})
+	}
+	last := container.segments[len(container.segments)-1]
+	last.logs = append(last.logs, _log)
+	last.index.add(_log.content)
+	last.count++
+	size := int64(len(_log.content) + logOverhead)
+	last.size += size
+	container.memory_size += size
+	container.dropOldest()
+	container.spillOldest()
+}
+
+func (container *SegmentedLogContainer) Get(index int) *singleLog {
+	if index < container.First() || index >= container.Len() {
+		empty := emptyLog()
+		return &empty
+	}
+	segment := container.segments[(index-container.First())/segmentLines]
+	logs := container.segmentLogs(segment, true)
+	if logs == nil {
+		empty := emptyLog()
+		return &empty
+	}
+	return logs[index-segment.first]
+}
+
+// Search returns the indices of the logs from index `from` matching the query, oldest first
+func (container *SegmentedLogContainer) Search(query *logQuery, from int) []int {
+	indices := make([]int, 0)
+	for _, segment := range container.segments {
+		if segment.first+segment.count <= from || !segment.index.mayContain(query.literals) {
+			continue
+		}
+		// searching doesn't evict the segments that are shown
+		logs := container.segmentLogs(segment, false)
+		for i := utils.Max(from-segment.first, 0); i < len(logs); i++ {
+			if query.Match(logs[i].content) {
+				indices = append(indices, segment.first+i)
+			}
+		}
+	}
+	return indices
+}
+
+// Count counts the logs matching the query up to limit, newest first
+func (container *SegmentedLogContainer) Count(query *logQuery, limit int) int {
+	count := 0
+	for i := len(container.segments) - 1; i >= 0 && count < limit; i-- {
+		segment := container.segments[i]
+		if !segment.index.mayContain(query.literals) {
+			continue
+		}
+		for _, _log := range container.segmentLogs(segment, false) {
+			if query.Match(_log.content) {
+				count++
+				if count == limit {
+					break
+				}
+			}
+		}
+	}
+	return count
+}
+
+// Scan returns the indices of the logs from index `from` that match
+func (container *SegmentedLogContainer) Scan(from int, match func(*singleLog) bool) []int {
+	indices := make([]int, 0)
+	for _, segment := range container.segments {
+		if segment.first+segment.count <= from {
+			continue
+		}
+		logs := container.segmentLogs(segment, false)
+		for i := utils.Max(from-segment.first, 0); i < len(logs); i++ {
+			if match(logs[i]) {
+				indices = append(indices, segment.first+i)
+			}
+		}
+	}
+	return indices
+}
+
+// Close deletes the spilled segments
+func (container *SegmentedLogContainer) Close() {
+	container.segments = nil
+	container.loaded = nil
+	container.memory_size = 0
+	if container.temp_dir != "" {
+		if err := os.RemoveAll(container.temp_dir); err != nil {
+			log.Printf("Failed to remove spilled logs %s: %s", container.temp_dir, err)
+		}
+		container.temp_dir = ""
+	}
+}
+
+// dropOldest drops the oldest segment once the logs after it are enough to fill the container
+func (container *SegmentedLogContainer) dropOldest() {
+	for len(container.segments) > 1 && container.Len()-container.segments[1].first >= container.max_lines {
+		oldest := container.segments[0]
+		container.segments = container.segments[1:]
+		if oldest.path == "" {
+			container.memory_size -= oldest.size
+		} else {
+			container.unload(oldest)
+			os.Remove(oldest.path)
+		}
+	}
+}
+
+// spillOldest writes the oldest full segments in memory to files until the rest fit in the memory limit
+func (container *SegmentedLogContainer) spillOldest() {
+	for _, segment := range container.segments[:len(container.segments)-1] {
+		if container.memory_size <= container.memory_limit {
+			return
+		}
+		if segment.path != "" {
+			continue
+		}
+		if err := container.spill(segment); err != nil {
+			// keep it in memory rather than losing it
+			log.Printf("Failed to spill logs to disk: %s", err)
+			return
+		}
+		container.memory_size -= segment.size
+		segment.logs = nil
+	}
+}
+
+func (container *SegmentedLogContainer) spill(segment *logSegment) error {
+	if container.temp_dir == "" {
+		temp_dir, err := os.MkdirTemp(utils.TempFolderPath(), "dc-top-logs-")
+		if err != nil {
+			return err
+		}
+		container.temp_dir = temp_dir
+	}
+	path := filepath.Join(container.temp_dir, fmt.Sprintf("segment-%d", container.spilled))
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := writeSegment(file, segment.logs); err != nil {
+		os.Remove(path)
+		return err
+	}
+	container.spilled++
+	segment.path = path
+	return nil
+}
+
+// segmentLogs returns the logs of the segment, loading a spilled one. Loaded segments are kept only when keep is set
+func (container *SegmentedLogContainer) segmentLogs(segment *logSegment, keep bool) []*singleLog {
+	if segment.path == "" || segment.logs != nil {
+		if keep && segment.path != "" {
+			container.unload(segment)
+			container.loaded = append(container.loaded, segment)
+		}
+		return segment.logs
+	}
+	logs, err := readSegment(segment.path, segment.count)
+	if err != nil {
+		log.Printf("Failed to read spilled logs %s: %s", segment.path, err)
+		return nil
+	}
+	if keep {
+		if len(container.loaded) == loadedSegments {
+			container.loaded[0].logs = nil
+			container.loaded = container.loaded[1:]
+		}
+		segment.logs = logs
+		container.loaded = append(container.loaded, segment)
+	}
+	return logs
+}
+
+// unload removes the segment from the loaded segments without dropping its logs
+func (container *SegmentedLogContainer) unload(segment *logSegment) {
+	for i, loaded := range container.loaded {
+		if loaded == segment {
+			container.loaded = append(container.loaded[:i], container.loaded[i+1:]...)
+			return
+		}
+	}
+}
+
+// every log is written as its time in unix nanoseconds, whether it's stdout, its source and its length prefixed
+// content
+func writeSegment(file io.Writer, logs []*singleLog) error {
+	writer := bufio.NewWriter(file)
+	header := make([]byte, 9+2*binary.MaxVarintLen64)
+	for _, _log := range logs {
+		var nanos int64
+		if !_log.time.IsZero() {
+			nanos = _log.time.UnixNano()
+		}
+		binary.BigEndian.PutUint64(header, uint64(nanos))
+		header[8] = 0
+		if _log.is_stdout {
+			header[8] = 1
+		}
+		n := 9 + binary.PutUvarint(header[9:], uint64(_log.source))
+		n += binary.PutUvarint(header[n:], uint64(len(_log.content)))
+		if _, err := writer.Write(header[:n]); err != nil {
+			return err
+		}
+		if _, err := writer.WriteString(_log.content); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+func readSegment(path string, count int) ([]*singleLog, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	logs := make([]*singleLog, 0, count)
+	header := make([]byte, 9)
+	for len(logs) < count {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return nil, err
+		}
+		source, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		content := make([]byte, length)
+		if _, err := io.ReadFull(reader, content); err != nil {
+			return nil, err
+		}
+		var log_time time.Time
+		if nanos := int64(binary.BigEndian.Uint64(header)); nanos != 0 {
+			log_time = time.Unix(0, nanos)
+		}
+		_log := newLog(log_time, string(content), header[8] == 1)
+		_log.source = int(source)
+		logs = append(logs, &_log)
+	}
+	return logs, nil
+}
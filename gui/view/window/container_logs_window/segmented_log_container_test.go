@@ -0,0 +1,64 @@
+package container_logs_window
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func appendLogs(container *SegmentedLogContainer, from, to int) {
+	start := time.Date(2022, 5, 1, 13, 0, 0, 0, time.UTC)
+	for i := from; i < to; i++ {
+		_log := newLog(start.Add(time.Duration(i)*time.Millisecond), fmt.Sprintf("line %d", i), i%3 != 0)
+		_log.source = i % 4
+		container.Append(&_log)
+	}
+}
+
+func TestSegmentedLogContainerSpillsToDisk(t *testing.T) {
+	// a few segments fit in memory, the rest is spilled
+	container := NewSegmentedLogContainer(10*segmentLines, 2*segmentLines*(logOverhead+12))
+	defer container.Close()
+	total := 5*segmentLines + 10
+	appendLogs(container, 0, total)
+	if container.temp_dir == "" || container.segments[0].path == "" {
+		t.Fatal("expected the oldest segments to be spilled")
+	}
+	for _, i := range []int{0, segmentLines - 1, segmentLines, 3*segmentLines + 7, total - 1} {
+		_log := container.Get(i)
+		if _log.content != fmt.Sprintf("line %d", i) || _log.is_stdout != (i%3 != 0) || _log.source != i%4 || _log.time.UnixMilli()%1000 != int64(i%1000) {
+			t.Errorf("log %d: got %+v", i, *_log)
+		}
+	}
+	// 1234 and 12340-12349
+	query, _ := newLogQuery("LINE 1234", searchOptions{})
+	if indices := container.Search(query, 0); len(indices) != 11 || indices[0] != 1234 {
+		t.Errorf("unexpected search results, %d indices", len(indices))
+	}
+	if count := container.Count(query, 5); count != 5 {
+		t.Errorf("expected the count to stop at 5, got %d", count)
+	}
+	if count := container.Count(query, 100); count != 11 {
+		t.Errorf("expected 11 matches, got %d", count)
+	}
+}
+
+func TestSegmentedLogContainerDropsOldest(t *testing.T) {
+	container := NewSegmentedLogContainer(2*segmentLines, 0)
+	appendLogs(container, 0, 4*segmentLines+1)
+	if container.First() != 2*segmentLines || container.Len() != 4*segmentLines+1 {
+		t.Errorf("expected logs %d-%d, got %d-%d", 2*segmentLines, 4*segmentLines+1, container.First(), container.Len())
+	}
+	if content := container.Get(container.First() - 1).content; content != "" {
+		t.Errorf("expected dropped logs to be empty, got %q", content)
+	}
+	temp_dir := container.temp_dir
+	if entries, _ := os.ReadDir(temp_dir); len(entries) != 2 {
+		t.Errorf("expected the dropped segments' files to be deleted, %d files are left", len(entries))
+	}
+	container.Close()
+	if _, err := os.Stat(temp_dir); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed", temp_dir)
+	}
+}
@@ -1,12 +1,18 @@
 package container_logs_window
 
+import "time"
+
 type singleLog struct {
+	time      time.Time
 	content   string
 	is_stdout bool
+	// the index of the container that wrote it, in a merged view
+	source int
 }
 
-func newLog(content string, is_stdout bool) singleLog {
+func newLog(log_time time.Time, content string, is_stdout bool) singleLog {
 	return singleLog{
+		time:      log_time,
 		content:   content,
 		is_stdout: is_stdout,
 	}
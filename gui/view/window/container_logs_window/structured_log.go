@@ -0,0 +1,254 @@
+package container_logs_window
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type logFormat uint8
+
+const (
+	jsonFormat logFormat = iota
+	logfmtFormat
+)
+
+type logField struct {
+	key   string
+	value string
+}
+
+// structuredLog is a JSON or logfmt log split into fields, in the order they were written
+type structuredLog struct {
+	format  logFormat
+	fields  []logField
+	time    string
+	level   logLevel
+	message string
+}
+
+type logLevel uint8
+
+const (
+	noLevel logLevel = iota
+	traceLevel
+	debugLevel
+	infoLevel
+	warnLevel
+	errorLevel
+	fatalLevel
+)
+
+var levelNames = []string{"", "TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+func (level logLevel) String() string {
+	return levelNames[level]
+}
+
+// the keys common loggers write the time, level and message under
+var (
+	timeKeys    = []string{"time", "ts", "timestamp", "@timestamp", "t"}
+	levelKeys   = []string{"level", "lvl", "severity", "log.level", "@level"}
+	messageKeys = []string{"msg", "message", "@message"}
+)
+
+// parseLevel accepts level names, their common abbreviations and the numeric levels of pino and bunyan
+func parseLevel(value string) logLevel {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "trace", "trc", "10":
+		return traceLevel
+	case "debug", "dbg", "d", "20":
+		return debugLevel
+	case "info", "inf", "i", "information", "notice", "30":
+		return infoLevel
+	case "warn", "warning", "wrn", "w", "40":
+		return warnLevel
+	case "error", "err", "erro", "e", "50":
+		return errorLevel
+	case "fatal", "ftl", "panic", "critical", "crit", "emerg", "alert", "dpanic", "60":
+		return fatalLevel
+	}
+	return noLevel
+}
+
+// parseStructured parses a JSON object or a logfmt line, other logs aren't structured
+func parseStructured(content string) (*structuredLog, bool) {
+	var fields []logField
+	var format logFormat
+	if trimmed := strings.TrimSpace(content); strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}") {
+		var ok bool
+		if fields, ok = parseJSONFields(trimmed); !ok {
+			return nil, false
+		}
+		format = jsonFormat
+	} else {
+		var ok bool
+		if fields, ok = parseLogfmtFields(content); !ok {
+			return nil, false
+		}
+		format = logfmtFormat
+	}
+	structured := &structuredLog{format: format}
+	for _, field := range fields {
+		key := strings.ToLower(field.key)
+		switch {
+		case structured.time == "" && contains(timeKeys, key):
+			structured.time = field.value
+		case structured.level == noLevel && contains(levelKeys, key) && parseLevel(field.value) != noLevel:
+			structured.level = parseLevel(field.value)
+		case structured.message == "" && contains(messageKeys, key):
+			structured.message = field.value
+		default:
+			structured.fields = append(structured.fields, field)
+		}
+	}
+	return structured, true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// parseJSONFields keeps the order of the keys, which a map would lose. Nested values are kept as compact JSON
+func parseJSONFields(content string) ([]logField, bool) {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+	fields := make([]logField, 0)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, false
+		}
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, false
+		}
+		fields = append(fields, logField{key: key, value: jsonValue(raw)})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+func jsonValue(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, raw); err == nil {
+		return compacted.String()
+	}
+	return string(raw)
+}
+
+// parseLogfmtFields parses `key=value key="quoted value"` lines. Every token has to be a pair, and there have to be
+// at least two, so that text with a few `=` in it isn't taken for logfmt
+func parseLogfmtFields(content string) ([]logField, bool) {
+	fields := make([]logField, 0)
+	for i := 0; i < len(content); {
+		if content[i] == ' ' || content[i] == '\t' {
+			i++
+			continue
+		}
+		key_start := i
+		for i < len(content) && content[i] != '=' && content[i] != ' ' && content[i] != '"' {
+			i++
+		}
+		key := content[key_start:i]
+		if key == "" || !isLogfmtKey(key) {
+			return nil, false
+		}
+		if i == len(content) || content[i] != '=' {
+			return nil, false
+		}
+		i++
+		value := ""
+		if i < len(content) && content[i] == '"' {
+			end := i + 1
+			for end < len(content) && content[end] != '"' {
+				if content[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(content) {
+				return nil, false
+			}
+			unquoted, err := strconv.Unquote(content[i : end+1])
+			if err != nil {
+				return nil, false
+			}
+			value = unquoted
+			i = end + 1
+		} else {
+			value_start := i
+			for i < len(content) && content[i] != ' ' && content[i] != '\t' {
+				i++
+			}
+			value = content[value_start:i]
+		}
+		fields = append(fields, logField{key: key, value: value})
+	}
+	return fields, len(fields) >= 2
+}
+
+func isLogfmtKey(key string) bool {
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_.-@/", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// field returns the value of a key, the time, level and message are found by any of their keys
+func (structured *structuredLog) field(key string) (string, bool) {
+	lower := strings.ToLower(key)
+	switch {
+	case contains(timeKeys, lower) && structured.time != "":
+		return structured.time, true
+	case contains(levelKeys, lower) && structured.level != noLevel:
+		return structured.level.String(), true
+	case contains(messageKeys, lower) && structured.message != "":
+		return structured.message, true
+	}
+	for _, field := range structured.fields {
+		if field.key == key {
+			return field.value, true
+		}
+	}
+	return "", false
+}
+
+// details is the whole log for the details popup, JSON is pretty printed
+func (structured *structuredLog) details(content string) []byte {
+	if structured.format == jsonFormat {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, []byte(strings.TrimSpace(content)), "", "  "); err == nil {
+			return indented.Bytes()
+		}
+	}
+	fields, _ := parseLogfmtFields(content)
+	var details strings.Builder
+	for _, field := range fields {
+		fmt.Fprintf(&details, "%s: %s\n", field.key, field.value)
+	}
+	return []byte(details.String())
+}
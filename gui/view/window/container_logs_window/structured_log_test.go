@@ -0,0 +1,61 @@
+package container_logs_window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseStructuredLogs(t *testing.T) {
+	json_log, ok := parseStructured(`{"ts":"2022-05-01T13:00:00Z","level":"warning","msg":"slow query","took_ms":1200,"db":{"name":"users"}}`)
+	if !ok {
+		t.Fatal("expected a JSON log")
+	}
+	if json_log.time != "2022-05-01T13:00:00Z" || json_log.level != warnLevel || json_log.message != "slow query" {
+		t.Errorf("unexpected JSON log %+v", json_log)
+	}
+	text, _ := renderStructured(json_log, nil)
+	if expected := `2022-05-01T13:00:00Z WARN  slow query took_ms=1200 db={"name":"users"}`; text != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+
+	logfmt_log, ok := parseStructured(`level=30 msg="request done" request_id=abc status=200`)
+	if !ok || logfmt_log.level != infoLevel || logfmt_log.message != "request done" {
+		t.Errorf("unexpected logfmt log %+v", logfmt_log)
+	}
+	for _, content := range []string{"server started on port 8080", "x=1 is odd", "Connecting to db host=foo port=5432", `{"unterminated": `} {
+		if _, ok := parseStructured(content); ok {
+			t.Errorf("%q isn't structured", content)
+		}
+	}
+}
+
+func TestFieldFilters(t *testing.T) {
+	cases := []struct {
+		filter  string
+		content string
+		matches bool
+	}{
+		{"level>=warn", `{"level":"error","msg":"failed"}`, true},
+		{"level>=warn", `level=info msg=started`, false},
+		{"level>=warn", `plain text`, false},
+		{"request_id=abc", `{"msg":"done","request_id":"abc"}`, true},
+		{"request_id=abc", `plain request_id=abc line`, true},
+		{"status>=500", `msg=done status=503`, true},
+		{"status>=500", `msg=done status=404`, false},
+		{"-v level=debug", `level=debug msg=noise`, false},
+	}
+	for _, c := range cases {
+		filter, err := parseFilter(c.filter, searchOptions{})
+		if err != nil {
+			t.Fatalf("%q: %s", c.filter, err)
+		}
+		filters := logFilters{filters: []logFilter{filter}}
+		_log := newLog(time.Time{}, c.content, true)
+		if filters.match(&_log) != c.matches {
+			t.Errorf("%q on %q: expected %t", c.filter, c.content, c.matches)
+		}
+	}
+	if _, err := parseFilter("level>=loud", searchOptions{}); err == nil {
+		t.Error("expected an unknown level to fail")
+	}
+}
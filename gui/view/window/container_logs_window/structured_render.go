@@ -0,0 +1,81 @@
+package container_logs_window
+
+import (
+	"dc-top/gui/elements"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+var levelStyles = map[logLevel]tcell.Style{
+	traceLevel: tcell.StyleDefault.Foreground(tcell.ColorGray),
+	debugLevel: tcell.StyleDefault.Foreground(tcell.ColorGray),
+	infoLevel:  tcell.StyleDefault.Foreground(tcell.ColorGreen),
+	warnLevel:  tcell.StyleDefault.Foreground(tcell.ColorYellow),
+	errorLevel: tcell.StyleDefault.Foreground(tcell.ColorRed),
+	fatalLevel: tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true).Reverse(true),
+}
+
+var (
+	structuredTimeStyle = tcell.StyleDefault.Foreground(tcell.ColorDarkCyan)
+	structuredKeyStyle  = tcell.StyleDefault.Foreground(tcell.ColorDarkCyan)
+)
+
+// styledText is text with a style for each of its bytes
+type styledText struct {
+	text   strings.Builder
+	styles []tcell.Style
+}
+
+func (styled *styledText) write(text string, style tcell.Style) {
+	styled.text.WriteString(text)
+	for i := 0; i < len(text); i++ {
+		styled.styles = append(styled.styles, style)
+	}
+}
+
+// renderStructured renders the log as `time level message key=value...` and highlights the query matches
+func renderStructured(structured *structuredLog, query *logQuery) (string, elements.StringStyler) {
+	styled := &styledText{}
+	separate := func() {
+		if styled.text.Len() > 0 {
+			styled.write(" ", tcell.StyleDefault)
+		}
+	}
+	if structured.time != "" {
+		styled.write(structured.time, structuredTimeStyle)
+	}
+	if structured.level != noLevel {
+		separate()
+		styled.write(fmt.Sprintf("%-5s", structured.level), levelStyles[structured.level])
+	}
+	if structured.message != "" {
+		separate()
+		styled.write(structured.message, tcell.StyleDefault.Bold(true))
+	}
+	for _, field := range structured.fields {
+		separate()
+		styled.write(field.key+"=", structuredKeyStyle)
+		value := field.value
+		if strings.ContainsAny(value, " \t") || value == "" {
+			value = fmt.Sprintf("%q", value)
+		}
+		styled.write(value, tcell.StyleDefault)
+	}
+	text := styled.text.String()
+	styles := styled.styles
+	if query != nil {
+		for _, match := range query.Matches(text) {
+			for i := match[0]; i < match[1]; i++ {
+				styles[i] = styles[i].Reverse(true)
+			}
+		}
+	}
+	return text, func(i int) (rune, tcell.Style) {
+		if i < len(text) {
+			return rune(text[i]), styles[i]
+		}
+		return '\x00', tcell.StyleDefault
+	}
+}
@@ -3,28 +3,24 @@ package containers_window
 import (
 	"context"
 	docker "dc-top/docker"
+	"dc-top/docker/compose"
+	"dc-top/gui/view/window"
+	"dc-top/gui/view/window/bar_window"
+	"fmt"
 	"log"
 	"strings"
 )
 
-func (w *ContainersWindow) handleDelete(ctx context.Context, table_state *tableState) error {
-	index, err := findIndexOfId(table_state.containers_data.GetData(), table_state.focused_id)
-	if err != nil {
-		return err
-	}
-	go func(id_to_delete string) {
-		if err := docker.DeleteContainer(ctx, id_to_delete); err != nil {
-			log.Printf("Got error '%s' when trying to container delete %s", err, id_to_delete)
-			if !strings.Contains(err.Error(), "is already in progress") &&
-				!strings.Contains(err.Error(), "No such container") &&
-				!strings.Contains(err.Error(), "context canceled") {
-				panic(err)
-			}
+func (w *ContainersWindow) handleDelete(ctx context.Context, table_state *tableState, targets []docker.ContainerDatum) {
+	if _, err := findIndexOfId(targets, table_state.focused_id); err == nil {
+		index, err := findIndexOfId(table_state.containers_data.GetData(), table_state.focused_id)
+		if err == nil {
+			change_to_next := index != (table_state.containers_data.Len() - 1)
+			handleChangeIndex(change_to_next, table_state)
 		}
-	}(table_state.focused_id)
-	change_to_next := index != (table_state.containers_data.Len() - 1)
-	handleChangeIndex(change_to_next, table_state)
-	return nil
+	}
+	w.handleBulkAction(ctx, bulkDelete, targets)
+	table_state.selected_ids = make(map[string]bool)
 }
 
 func (w *ContainersWindow) handlePause(ctx context.Context, id string) {
@@ -65,3 +61,28 @@ func (w *ContainersWindow) handleRestart(ctx context.Context, id string) {
 		}
 	}(id)
 }
+
+func (w *ContainersWindow) handleComposeDown(ctx context.Context) {
+	bar_window.Info([]rune("Removing docker compose..."))
+	go func() {
+		if msg, err := compose.Down(ctx); err != nil {
+			bar_window.Err([]rune("Failed to remove docker compose"))
+			full_message := "Got error when removing docker-compose:\n" + string(msg)
+			window.GetScreen().PostEvent(window.NewChangeToErrorEvent([]byte(full_message)))
+		}
+	}()
+}
+
+func (w *ContainersWindow) handleRename(ctx context.Context, id string, new_name string) {
+	if new_name == "" {
+		return
+	}
+	go func(id_to_rename string) {
+		if err := docker.RenameContainer(ctx, id_to_rename, new_name); err != nil {
+			log.Printf("Got error '%s' when trying to rename container %s", err, id_to_rename)
+			bar_window.Err([]rune(fmt.Sprintf("Failed to rename container: %s", err)))
+		} else {
+			bar_window.Info([]rune(fmt.Sprintf("Renamed container to %s", new_name)))
+		}
+	}(id)
+}
@@ -0,0 +1,130 @@
+package containers_window
+
+import (
+	"context"
+	docker "dc-top/docker"
+	"dc-top/gui/view/window"
+	"dc-top/gui/view/window/bar_window"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+type bulkAction struct {
+	description string
+	action      func(ctx context.Context, datum docker.ContainerDatum) error
+}
+
+var (
+	bulkStart = bulkAction{
+		description: "Starting",
+		action: func(ctx context.Context, datum docker.ContainerDatum) error {
+			return docker.StartContainer(ctx, datum.ID())
+		},
+	}
+	bulkStop = bulkAction{
+		description: "Stopping",
+		action: func(ctx context.Context, datum docker.ContainerDatum) error {
+			return docker.StopContainer(ctx, datum.ID())
+		},
+	}
+	bulkRestart = bulkAction{
+		description: "Restarting",
+		action: func(ctx context.Context, datum docker.ContainerDatum) error {
+			return docker.RestartContainer(ctx, datum.ID())
+		},
+	}
+	bulkDelete = bulkAction{
+		description: "Removing",
+		action: func(ctx context.Context, datum docker.ContainerDatum) error {
+			return docker.DeleteContainer(ctx, datum.ID())
+		},
+	}
+)
+
+// bulkPause unpauses the paused targets and pauses the rest
+func bulkPause(targets []docker.ContainerDatum) bulkAction {
+	paused := 0
+	for _, target := range targets {
+		if target.State() == "paused" {
+			paused++
+		}
+	}
+	description := "Pausing"
+	if paused == len(targets) {
+		description = "Unpausing"
+	} else if paused > 0 {
+		description = "Pausing/unpausing"
+	}
+	return bulkAction{
+		description: description,
+		action: func(ctx context.Context, datum docker.ContainerDatum) error {
+			if datum.State() == "paused" {
+				return docker.UnpauseContainer(ctx, datum.ID())
+			}
+			return docker.PauseContainer(ctx, datum.ID())
+		},
+	}
+}
+
+func bulkSignal(signal string) bulkAction {
+	return bulkAction{
+		description: fmt.Sprintf("Sending %s to", signal),
+		action: func(ctx context.Context, datum docker.ContainerDatum) error {
+			return docker.KillContainer(ctx, datum.ID(), signal)
+		},
+	}
+}
+
+type bulkResult struct {
+	datum docker.ContainerDatum
+	err   error
+}
+
+func (w *ContainersWindow) handleBulkAction(ctx context.Context, action bulkAction, targets []docker.ContainerDatum) {
+	if len(targets) == 0 {
+		return
+	}
+	bar_window.Info([]rune(fmt.Sprintf("%s %d containers...", action.description, len(targets))))
+	go func() {
+		results := make(chan bulkResult, len(targets))
+		var wg sync.WaitGroup
+		for _, target := range targets {
+			wg.Add(1)
+			go func(_inner_target docker.ContainerDatum) {
+				defer wg.Done()
+				results <- bulkResult{datum: _inner_target, err: action.action(ctx, _inner_target)}
+			}(target)
+		}
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		failures := make([]bulkResult, 0)
+		done := 0
+		for result := range results {
+			done++
+			if result.err != nil {
+				log.Printf("Got error '%s' when %s container %s", result.err, strings.ToLower(action.description), result.datum.ID())
+				failures = append(failures, result)
+			}
+			bar_window.Info([]rune(fmt.Sprintf("%s containers: %d/%d done, %d failed", action.description, done, len(targets), len(failures))))
+		}
+
+		if len(failures) > 0 {
+			bar_window.Err([]rune(fmt.Sprintf("%s %d/%d containers failed", action.description, len(failures), len(targets))))
+			window.GetScreen().PostEvent(window.NewChangeToErrorEvent(bulkFailureReport(action, failures, len(targets))))
+		}
+	}()
+}
+
+func bulkFailureReport(action bulkAction, failures []bulkResult, total int) []byte {
+	var report strings.Builder
+	report.WriteString(fmt.Sprintf("%s %d out of %d containers failed:\n", action.description, len(failures), total))
+	for _, failure := range failures {
+		report.WriteString(fmt.Sprintf("%s (%.12s): %s\n", failure.datum.CachedStats().Name, failure.datum.ID(), failure.err))
+	}
+	return []byte(report.String())
+}
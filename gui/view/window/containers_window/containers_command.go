@@ -0,0 +1,49 @@
+package containers_window
+
+import (
+	"dc-top/config"
+	"dc-top/docker"
+	"dc-top/gui/view/window"
+	"dc-top/gui/view/window/bar_window"
+	"fmt"
+	"log"
+	"strings"
+)
+
+type commandRequest struct {
+	targets []window.CommandTarget
+	command string
+}
+
+// openCommandPrompt asks for a command to run in the running containers among the targets
+func openCommandPrompt(targets []docker.ContainerDatum) {
+	var command_targets []window.CommandTarget
+	var names []string
+	for _, target := range targets {
+		if target.State() != "running" {
+			continue
+		}
+		command_targets = append(command_targets, window.CommandTarget{Id: target.ID(), Name: target.CachedStats().Name})
+		names = append(names, target.CachedStats().Name)
+	}
+	if len(command_targets) == 0 {
+		bar_window.Err([]rune("No running container to run a command in"))
+		return
+	}
+	title := fmt.Sprintf("Run in %s (Up/Down for history)", strings.Join(names, ", "))
+	window.GetScreen().PostEvent(window.NewChangeToPromptWithHistoryEvent(title, config.CommandHistory(), window.ContainersHolder,
+		func(command string) interface{} {
+			return commandRequest{targets: command_targets, command: command}
+		}))
+}
+
+func handleCommandRequest(request commandRequest) {
+	command := strings.TrimSpace(request.command)
+	if command == "" {
+		return
+	}
+	if err := config.AddToCommandHistory(command); err != nil {
+		log.Printf("Failed to save command history: %s", err)
+	}
+	window.GetScreen().PostEvent(window.NewChangeToCommandOutputEvent(command, request.targets))
+}
@@ -0,0 +1,59 @@
+package containers_window
+
+import (
+	docker "dc-top/docker"
+	"dc-top/gui/view/window"
+	"fmt"
+	"strings"
+)
+
+type confirmedDelete struct {
+	targets []docker.ContainerDatum
+}
+
+type confirmedKill struct {
+	targets []docker.ContainerDatum
+	signal  string
+}
+
+type confirmedComposeDown struct{}
+
+func requestDeleteConfirmation(targets []docker.ContainerDatum) {
+	if len(targets) == 0 {
+		return
+	}
+	prompt := fmt.Sprintf("Remove %s (including its volumes)?", describeTargets(targets))
+	window.GetScreen().PostEvent(window.NewChangeToConfirmEvent(prompt, confirmationText(targets), window.ContainersHolder, confirmedDelete{targets: targets}))
+}
+
+func requestKillConfirmation(targets []docker.ContainerDatum, signal string) {
+	if len(targets) == 0 {
+		return
+	}
+	prompt := fmt.Sprintf("Send %s to %s?", signal, describeTargets(targets))
+	window.GetScreen().PostEvent(window.NewChangeToConfirmEvent(prompt, "", window.ContainersHolder, confirmedKill{targets: targets, signal: signal}))
+}
+
+func requestComposeDownConfirmation() {
+	prompt := "Remove (down) all docker compose containers and networks?"
+	window.GetScreen().PostEvent(window.NewChangeToConfirmEvent(prompt, "down", window.ContainersHolder, confirmedComposeDown{}))
+}
+
+func describeTargets(targets []docker.ContainerDatum) string {
+	if len(targets) == 1 {
+		return fmt.Sprintf("container '%s'", targets[0].CachedStats().Name)
+	}
+	names := make([]string, len(targets))
+	for i, target := range targets {
+		names[i] = target.CachedStats().Name
+	}
+	return fmt.Sprintf("%d containers:\n%s\n", len(targets), strings.Join(names, ", "))
+}
+
+// the user has to type the container name, or the number of containers for bulk removals
+func confirmationText(targets []docker.ContainerDatum) string {
+	if len(targets) == 1 {
+		return targets[0].CachedStats().Name
+	}
+	return fmt.Sprintf("%d", len(targets))
+}
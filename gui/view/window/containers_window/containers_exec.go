@@ -0,0 +1,102 @@
+package containers_window
+
+import (
+	"dc-top/config"
+	docker "dc-top/docker"
+	"dc-top/gui/view/window"
+	"dc-top/gui/view/window/bar_window"
+	"dc-top/utils"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+var execFormLabels = []string{
+	"Command (empty = detect shell)",
+	"User (e.g. root)",
+	"Working directory",
+	"Env (KEY=value ...)",
+	"Privileged (y/n)",
+}
+
+type execRequest struct {
+	id     string
+	image  string
+	values []string
+}
+
+// openShell runs the command last chosen for the container's image, or a detected shell
+func openShell(datum docker.ContainerDatum) {
+	choice, _ := config.GetExecChoice(datum.Image())
+	spec, err := execSpecFromChoice(choice)
+	if err != nil {
+		log.Printf("Ignoring bad exec choice of %s: %s", datum.Image(), err)
+		spec = docker.ExecSpec{}
+	}
+	window.GetScreen().PostEvent(window.NewChangeToContainerShellEvent(datum.ID(), spec))
+}
+
+func openExecForm(datum docker.ContainerDatum) {
+	id := datum.ID()
+	image := datum.Image()
+	choice, _ := config.GetExecChoice(image)
+	privileged := "n"
+	if choice.Privileged {
+		privileged = "y"
+	}
+	values := []string{choice.Command, choice.User, choice.WorkingDir, utils.QuoteArgs(choice.Env), privileged}
+	actions := []window.FormAction{
+		{
+			Key:     tcell.KeyEnter,
+			KeyName: "Enter",
+			Label:   "exec",
+			MessageGenerator: func(values []string) interface{} {
+				return execRequest{id: id, image: image, values: values}
+			},
+		},
+	}
+	window.GetScreen().PostEvent(window.NewChangeToFormEvent(fmt.Sprintf("Exec in %s", datum.CachedStats().Name), execFormLabels, values, actions, window.ContainersHolder))
+}
+
+func handleExecRequest(request execRequest) {
+	choice := config.ExecChoice{
+		Command:    strings.TrimSpace(request.values[0]),
+		User:       strings.TrimSpace(request.values[1]),
+		WorkingDir: strings.TrimSpace(request.values[2]),
+		Env:        utils.SplitArgs(request.values[3]),
+	}
+	switch strings.ToLower(strings.TrimSpace(request.values[4])) {
+	case "y", "yes":
+		choice.Privileged = true
+	case "", "n", "no":
+	default:
+		bar_window.Err([]rune(fmt.Sprintf("Bad privileged value '%s' (y/n)", request.values[4])))
+		return
+	}
+	spec, err := execSpecFromChoice(choice)
+	if err != nil {
+		bar_window.Err([]rune(err.Error()))
+		return
+	}
+	if err = config.SaveExecChoice(request.image, choice); err != nil {
+		log.Printf("Failed to save exec choice of %s: %s", request.image, err)
+	}
+	window.GetScreen().PostEvent(window.NewChangeToContainerShellEvent(request.id, spec))
+}
+
+func execSpecFromChoice(choice config.ExecChoice) (docker.ExecSpec, error) {
+	for _, env := range choice.Env {
+		if !strings.Contains(env, "=") {
+			return docker.ExecSpec{}, fmt.Errorf("bad env value '%s' (KEY=value)", env)
+		}
+	}
+	return docker.ExecSpec{
+		Command:    utils.SplitArgs(choice.Command),
+		User:       choice.User,
+		WorkingDir: choice.WorkingDir,
+		Env:        choice.Env,
+		Privileged: choice.Privileged,
+	}, nil
+}
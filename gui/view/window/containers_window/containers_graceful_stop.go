@@ -0,0 +1,58 @@
+package containers_window
+
+import (
+	"context"
+	docker "dc-top/docker"
+	"dc-top/gui/view/window"
+	"dc-top/gui/view/window/bar_window"
+	"fmt"
+	"log"
+	"time"
+)
+
+func (w *ContainersWindow) handleGracefulStop(ctx context.Context, targets []docker.ContainerDatum) {
+	for _, target := range targets {
+		go gracefulStop(ctx, target)
+	}
+}
+
+// gracefulStop sends the stop signal and counts down the stop timeout in the bar, offering SIGKILL if it expires
+func gracefulStop(ctx context.Context, datum docker.ContainerDatum) {
+	name := datum.CachedStats().Name
+	timeout := docker.StopTimeout(ctx, datum.ID())
+	stopped := docker.WaitUntilStopped(ctx, datum.ID())
+	if err := docker.SendStopSignal(ctx, datum.ID()); err != nil {
+		log.Printf("Got error '%s' when trying to gracefully stop %s", err, datum.ID())
+		bar_window.Err([]rune(fmt.Sprintf("Failed to stop %s: %s", name, err)))
+		return
+	}
+
+	deadline := time.Now().Add(timeout)
+	bar_window.Info([]rune(fmt.Sprintf("Waiting for %s to stop: %s left", name, timeout)))
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case result := <-stopped:
+			if result.Err != nil {
+				log.Printf("Got error '%s' when waiting for %s to stop", result.Err, datum.ID())
+				bar_window.Err([]rune(fmt.Sprintf("Failed to wait for %s to stop: %s", name, result.Err)))
+			} else {
+				bar_window.Info([]rune(fmt.Sprintf("%s stopped gracefully (exit code %d)", name, result.ExitCode)))
+			}
+			return
+		case <-ticker.C:
+			time_left := time.Until(deadline).Round(time.Second)
+			if time_left <= 0 {
+				bar_window.Warn([]rune(fmt.Sprintf("%s didn't stop after %s", name, timeout)))
+				prompt := fmt.Sprintf("Container '%s' didn't stop after %s.\nSend SIGKILL?", name, timeout)
+				window.GetScreen().PostEvent(window.NewChangeToConfirmEvent(prompt, "", window.ContainersHolder,
+					confirmedKill{targets: []docker.ContainerDatum{datum}, signal: "SIGKILL"}))
+				return
+			}
+			bar_window.Info([]rune(fmt.Sprintf("Waiting for %s to stop: %s left", name, time_left)))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
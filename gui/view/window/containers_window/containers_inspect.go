@@ -1,6 +1,7 @@
 package containers_window
 
 import (
+	"dc-top/config"
 	docker "dc-top/docker"
 	"dc-top/gui/elements"
 	"fmt"
@@ -78,7 +79,9 @@ func generatePrettyInspectInfo(state tableState, window_width int) (map[int]elem
 	}
 	info_arr = append(info_arr, network_usage...)
 
-	info_arr = append(info_arr, generateInspectSeperator())
+	info_arr = append(info_arr, generateInspectSeperator(),
+		elements.TextDrawer("Filesystem changes: press 'D' to list what the container wrote to its writable layer", tcell.StyleDefault),
+		generateInspectSeperator())
 
 	var row_offset int
 	num_rows := len(info_arr)
@@ -98,6 +101,9 @@ func generateResourceUsageStyler(usage, quota, limit int64, resource, unit strin
 	var quota_desc string
 	if quota == 0 {
 		quota_desc = " Quota isn't set"
+		if !config.IsReadOnly() {
+			quota_desc += " (press 'L' to set)"
+		}
 		quota = limit
 	} else {
 		quota_desc = fmt.Sprintf(" Quota: %.2f%s", float64(quota)/float64(1<<30), unit)
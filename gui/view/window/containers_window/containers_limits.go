@@ -0,0 +1,181 @@
+package containers_window
+
+import (
+	"context"
+	docker "dc-top/docker"
+	"dc-top/gui/view/window"
+	"dc-top/gui/view/window/bar_window"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/gdamore/tcell/v2"
+)
+
+var limitsFormLabels = []string{
+	"CPUs",
+	"Memory (e.g. 512m)",
+	"Memory+Swap (-1 = unlimited)",
+	"CPU shares",
+	"PIDs limit (0 = unlimited)",
+	"Restart policy",
+}
+
+var errLimitRemoval = errors.New("CPU and memory limits can't be removed without recreating the container")
+
+type limitsUpdate struct {
+	id              string
+	name            string
+	original_values []string
+	values          []string
+}
+
+func openLimitsForm(ctx context.Context, datum docker.ContainerDatum) {
+	id := datum.ID()
+	name := datum.CachedStats().Name
+	go func() {
+		limits, err := docker.GetResourceLimits(ctx, id)
+		if err != nil {
+			bar_window.Err([]rune(fmt.Sprintf("Failed to get limits of %s: %s", name, err)))
+			return
+		}
+		original_values := formatLimits(limits)
+		actions := []window.FormAction{
+			{
+				Key:     tcell.KeyEnter,
+				KeyName: "Enter",
+				Label:   "apply",
+				MessageGenerator: func(values []string) interface{} {
+					return limitsUpdate{id: id, name: name, original_values: original_values, values: values}
+				},
+			},
+		}
+		window.GetScreen().PostEvent(window.NewChangeToFormEvent(fmt.Sprintf("Resource limits of %s", name), limitsFormLabels, original_values, actions, window.ContainersHolder))
+	}()
+}
+
+func (w *ContainersWindow) handleLimitsUpdate(ctx context.Context, update limitsUpdate) {
+	limits, err := parseLimits(update.original_values, update.values)
+	if err != nil {
+		bar_window.Err([]rune(fmt.Sprintf("Invalid limits: %s", err)))
+		return
+	}
+	go func() {
+		warnings, err := docker.UpdateResourceLimits(ctx, update.id, limits)
+		if err != nil {
+			log.Printf("Got error '%s' when updating limits of %s", err, update.id)
+			bar_window.Err([]rune(fmt.Sprintf("Failed to update limits of %s", update.name)))
+			window.GetScreen().PostEvent(window.NewChangeToErrorEvent([]byte(fmt.Sprintf("Failed to update limits of %s:\n%s", update.name, err))))
+			return
+		}
+		if len(warnings) > 0 {
+			bar_window.Warn([]rune(strings.Join(warnings, ", ")))
+		} else {
+			bar_window.Info([]rune(fmt.Sprintf("Updated limits of %s", update.name)))
+		}
+	}()
+}
+
+func formatLimits(limits docker.ResourceLimits) []string {
+	restart_policy := limits.RestartPolicy
+	if restart_policy == "" {
+		restart_policy = "no"
+	} else if restart_policy == "on-failure" && limits.MaximumRetryCount > 0 {
+		restart_policy = fmt.Sprintf("on-failure:%d", limits.MaximumRetryCount)
+	}
+	return []string{
+		strconv.FormatFloat(float64(limits.NanoCPUs)/1e9, 'f', -1, 64),
+		formatBytes(limits.Memory),
+		formatBytes(limits.MemorySwap),
+		strconv.FormatInt(limits.CPUShares, 10),
+		strconv.FormatInt(limits.PidsLimit, 10),
+		restart_policy,
+	}
+}
+
+func formatBytes(size int64) string {
+	if size <= 0 {
+		return strconv.FormatInt(size, 10)
+	}
+	return units.BytesSize(float64(size))
+}
+
+// parseLimits only parses the fields that were changed, so that only they're updated and values aren't affected by
+// formatting precision
+func parseLimits(original_values []string, values []string) (docker.ResourceLimitsUpdate, error) {
+	var limits docker.ResourceLimitsUpdate
+	changed := func(i int) bool { return strings.TrimSpace(values[i]) != original_values[i] }
+	var err error
+	if changed(0) {
+		var cpus float64
+		if cpus, err = strconv.ParseFloat(strings.TrimSpace(values[0]), 64); err != nil || cpus < 0 {
+			return limits, fmt.Errorf("bad CPUs value '%s'", values[0])
+		}
+		// the engine takes 0 as leaving the limit as is
+		if cpus == 0 {
+			return limits, errLimitRemoval
+		}
+		limits.NanoCPUs = int64(cpus * 1e9)
+	}
+	if changed(1) {
+		if limits.Memory, err = parseBytes(values[1]); err != nil {
+			return limits, fmt.Errorf("bad memory value '%s'", values[1])
+		}
+		if limits.Memory <= 0 {
+			return limits, errLimitRemoval
+		}
+	}
+	if changed(2) {
+		if limits.MemorySwap, err = parseBytes(values[2]); err != nil {
+			return limits, fmt.Errorf("bad memory+swap value '%s'", values[2])
+		}
+	}
+	if changed(3) {
+		if limits.CPUShares, err = strconv.ParseInt(strings.TrimSpace(values[3]), 10, 64); err != nil {
+			return limits, fmt.Errorf("bad CPU shares value '%s'", values[3])
+		}
+	}
+	if changed(4) {
+		pids_limit, err := strconv.ParseInt(strings.TrimSpace(values[4]), 10, 64)
+		if err != nil {
+			return limits, fmt.Errorf("bad PIDs limit value '%s'", values[4])
+		}
+		limits.PidsLimit = &pids_limit
+	}
+	if changed(5) {
+		if limits.RestartPolicy, limits.MaximumRetryCount, err = parseRestartPolicy(values[5]); err != nil {
+			return limits, err
+		}
+	}
+	return limits, nil
+}
+
+func parseBytes(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "0" || value == "-1" {
+		return strconv.ParseInt(value, 10, 64)
+	}
+	return units.RAMInBytes(value)
+}
+
+func parseRestartPolicy(value string) (string, int, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), ":", 2)
+	switch parts[0] {
+	case "no", "always", "unless-stopped":
+		if len(parts) == 1 {
+			return parts[0], 0, nil
+		}
+	case "on-failure":
+		if len(parts) == 1 {
+			return parts[0], 0, nil
+		}
+		retries, err := strconv.Atoi(parts[1])
+		if err == nil && retries >= 0 {
+			return parts[0], retries, nil
+		}
+	}
+	return "", 0, fmt.Errorf("bad restart policy '%s' (no, always, unless-stopped or on-failure[:max-retries])", value)
+}
@@ -0,0 +1,206 @@
+package containers_window
+
+import (
+	"dc-top/config"
+	docker "dc-top/docker"
+	"dc-top/gui/view/window"
+	"dc-top/gui/view/window/bar_window"
+	"fmt"
+)
+
+type containerAction uint8
+
+const (
+	shellAction containerAction = iota
+	execAction
+	attachAction
+	debugAction
+	logsAction
+	projectLogsAction
+	topAction
+	filesAction
+	diffAction
+	inspectAction
+	startAction
+	stopAction
+	gracefulStopAction
+	restartAction
+	pauseAction
+	unpauseAction
+	killAction
+	signalMenuAction
+	sendSignalAction
+	renameAction
+	limitsAction
+	removeAction
+)
+
+var containerActionLabels = map[containerAction]string{
+	shellAction:        "Open shell",
+	execAction:         "Exec...",
+	attachAction:       "Attach",
+	debugAction:        "Debug from sidecar",
+	logsAction:         "Logs",
+	projectLogsAction:  "Compose project logs",
+	topAction:          "Processes",
+	filesAction:        "Browse files",
+	diffAction:         "Filesystem changes",
+	inspectAction:      "Inspect",
+	startAction:        "Start",
+	stopAction:         "Stop",
+	gracefulStopAction: "Graceful stop",
+	restartAction:      "Restart",
+	pauseAction:        "Pause",
+	unpauseAction:      "Unpause",
+	killAction:         "Kill",
+	signalMenuAction:   "Send signal...",
+	renameAction:       "Rename...",
+	limitsAction:       "Resource limits...",
+	removeAction:       "Remove",
+}
+
+var readOnlyActions = map[containerAction]bool{
+	logsAction:        true,
+	projectLogsAction: true,
+	topAction:         true,
+	filesAction:       true,
+	diffAction:        true,
+	inspectAction:     true,
+}
+
+// the actions that make sense for each container state
+var validActions = map[string][]containerAction{
+	"running":    {shellAction, execAction, attachAction, debugAction, logsAction, projectLogsAction, topAction, filesAction, diffAction, inspectAction, stopAction, gracefulStopAction, restartAction, pauseAction, killAction, signalMenuAction, renameAction, limitsAction, removeAction},
+	"paused":     {logsAction, projectLogsAction, topAction, filesAction, diffAction, inspectAction, unpauseAction, stopAction, killAction, renameAction, limitsAction, removeAction},
+	"restarting": {logsAction, projectLogsAction, filesAction, diffAction, inspectAction, stopAction, killAction, renameAction, removeAction},
+	"created":    {logsAction, projectLogsAction, filesAction, diffAction, inspectAction, startAction, renameAction, limitsAction, removeAction},
+	"exited":     {logsAction, projectLogsAction, filesAction, diffAction, inspectAction, startAction, restartAction, renameAction, limitsAction, removeAction},
+	"dead":       {logsAction, projectLogsAction, filesAction, diffAction, inspectAction, removeAction},
+}
+
+var menuSignals = []string{"SIGTERM", "SIGINT", "SIGHUP", "SIGQUIT", "SIGUSR1", "SIGUSR2", "SIGKILL"}
+
+type menuSelection struct {
+	action containerAction
+	id     string
+	signal string
+	x, y   int
+}
+
+type renameRequest struct {
+	id       string
+	new_name string
+}
+
+func openContainerMenu(state *tableState, id string, x, y int) {
+	index, err := findIndexOfId(state.containers_data.GetData(), id)
+	if err != nil {
+		return
+	}
+	datum := state.containers_data.GetData()[index]
+	items := make([]window.MenuItem, 0)
+	for _, action := range validActions[datum.State()] {
+		if config.IsReadOnly() && !readOnlyActions[action] {
+			continue
+		}
+		if action == projectLogsAction && datum.ComposeProject() == "" {
+			continue
+		}
+		items = append(items, window.MenuItem{
+			Label:   containerActionLabels[action],
+			Message: menuSelection{action: action, id: id, x: x, y: y},
+		})
+	}
+	if len(items) == 0 {
+		bar_window.Warn([]rune(fmt.Sprintf("No actions available for %s containers", datum.State())))
+		return
+	}
+	window.GetScreen().PostEvent(window.NewChangeToMenuEvent(datum.CachedStats().Name, items, x, y, window.ContainersHolder))
+}
+
+func openSignalMenu(datum *docker.ContainerDatum, x, y int) {
+	items := make([]window.MenuItem, len(menuSignals))
+	for i, signal := range menuSignals {
+		items[i] = window.MenuItem{
+			Label:   signal,
+			Message: menuSelection{action: sendSignalAction, id: datum.ID(), signal: signal, x: x, y: y},
+		}
+	}
+	window.GetScreen().PostEvent(window.NewChangeToMenuEvent(fmt.Sprintf("Signal %s", datum.CachedStats().Name), items, x, y, window.ContainersHolder))
+}
+
+func handleMenuSelection(selection menuSelection, w *ContainersWindow, table_state tableState) tableState {
+	index, err := findIndexOfId(table_state.containers_data.GetData(), selection.id)
+	if err != nil {
+		bar_window.Err([]rune("Container doesn't exist anymore"))
+		return table_state
+	}
+	datum := table_state.containers_data.GetData()[index]
+	targets := []docker.ContainerDatum{datum}
+	screen := window.GetScreen()
+	switch selection.action {
+	case shellAction:
+		openShell(datum)
+	case execAction:
+		openExecForm(datum)
+	case attachAction:
+		screen.PostEvent(window.NewChangeToAttachEvent(datum.ID()))
+	case debugAction:
+		screen.PostEvent(window.NewChangeToDebugShellEvent(datum.ID()))
+	case logsAction:
+		screen.PostEvent(window.NewChangeToLogsWindowEvent(datum.ID()))
+	case projectLogsAction:
+		screen.PostEvent(window.NewChangeToLogsWindowEvent(projectContainerIds(&table_state, datum.ComposeProject())...))
+	case topAction:
+		screen.PostEvent(window.NewChangeToTopEvent(datum.ID()))
+	case filesAction:
+		screen.PostEvent(window.NewChangeToFileBrowserEvent(datum.ID()))
+	case diffAction:
+		screen.PostEvent(window.NewChangeToDiffEvent(datum.ID()))
+	case inspectAction:
+		table_state.focused_id = datum.ID()
+		table_state.window_mode = inspect
+	case startAction:
+		w.handleBulkAction(w.window_context, bulkStart, targets)
+	case stopAction:
+		w.handleBulkAction(w.window_context, bulkStop, targets)
+	case gracefulStopAction:
+		w.handleGracefulStop(w.window_context, targets)
+	case restartAction:
+		w.handleBulkAction(w.window_context, bulkRestart, targets)
+	case pauseAction, unpauseAction:
+		w.handleBulkAction(w.window_context, bulkPause(targets), targets)
+	case killAction:
+		requestKillConfirmation(targets, "SIGKILL")
+	case signalMenuAction:
+		openSignalMenu(&datum, selection.x, selection.y)
+	case sendSignalAction:
+		w.handleBulkAction(w.window_context, bulkSignal(selection.signal), targets)
+	case renameAction:
+		id := datum.ID()
+		screen.PostEvent(window.NewChangeToPromptEvent(
+			fmt.Sprintf("Rename container '%s' to:", datum.CachedStats().Name),
+			datum.CachedStats().Name,
+			window.ContainersHolder,
+			func(value string) interface{} { return renameRequest{id: id, new_name: value} },
+		))
+	case limitsAction:
+		openLimitsForm(w.window_context, datum)
+	case removeAction:
+		requestDeleteConfirmation(targets)
+	}
+	return table_state
+}
+
+// focusedRowPosition returns the screen position right under the focused row
+func focusedRowPosition(w *ContainersWindow, state *tableState) (int, int) {
+	dimensions := w.dimensions_generator()
+	index, err := findIndexOfId(state.filtered_data, state.focused_id)
+	if err != nil {
+		index = state.index_of_top_container
+	}
+	widths := getCellWidths(window.Width(&dimensions))
+	x := dimensions.LeftX + 1 + widths[0] + widths[1]
+	y := dimensions.TopY + 3 + index - state.index_of_top_container + 1
+	return x, y
+}
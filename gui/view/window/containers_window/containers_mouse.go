@@ -22,13 +22,17 @@ func handleMouseEvent(ev *tcell.EventMouse, w *ContainersWindow, table_state tab
 	case y == 1:
 		var new_sort_type docker.SortType = getSortTypeFromMousePress(total_width, x)
 		updateSortType(&table_state, new_sort_type)
-	case y > 2 && y < table_state.containers_data.Len()+3:
+	case y > 2 && y < len(table_state.filtered_data)+3:
 		i := table_state.index_of_top_container + y - 3
-		if i >= table_state.containers_data.Len() {
+		if i >= len(table_state.filtered_data) {
 			break
 		}
 		updateIndices(&table_state, i)
-		table_state.focused_id = table_state.containers_data.GetData()[i].ID()
+		table_state.focused_id = table_state.filtered_data[i].ID()
+		if ev.Buttons()&tcell.Button2 != 0 && table_state.window_mode == containers {
+			abs_x, abs_y := ev.Position()
+			openContainerMenu(&table_state, table_state.focused_id, abs_x, abs_y+1)
+		}
 	}
 	return table_state
 }
@@ -0,0 +1,51 @@
+package containers_window
+
+import (
+	"dc-top/config"
+	"dc-top/gui/view/window"
+	"dc-top/gui/view/window/bar_window"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type playRecordingRequest struct {
+	path string
+}
+
+// openRecordingsMenu lists the session recordings, newest first
+func openRecordingsMenu() {
+	dir := config.RecordingsDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		bar_window.Err([]rune(fmt.Sprintf("Failed to list recordings: %s", err)))
+		return
+	}
+	var recordings []os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".cast") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			recordings = append(recordings, info)
+		}
+	}
+	if len(recordings) == 0 {
+		bar_window.Info([]rune(fmt.Sprintf("No recordings in %s, press Ctrl+B r in a shell to record it", dir)))
+		return
+	}
+	sort.Slice(recordings, func(i, j int) bool {
+		return recordings[i].ModTime().After(recordings[j].ModTime())
+	})
+	items := make([]window.MenuItem, len(recordings))
+	for i, recording := range recordings {
+		items[i] = window.MenuItem{
+			Label:   strings.TrimSuffix(recording.Name(), ".cast"),
+			Message: playRecordingRequest{path: filepath.Join(dir, recording.Name())},
+		}
+	}
+	width, height := window.GetScreen().Size()
+	window.GetScreen().PostEvent(window.NewChangeToMenuEvent("Recordings", items, width/3, height/4, window.ContainersHolder))
+}
@@ -0,0 +1,37 @@
+package containers_window
+
+import (
+	"dc-top/gui/view/window"
+	"log"
+)
+
+// handleRequest runs what was confirmed or chosen in the popups the window opened
+func handleRequest(request interface{}, w *ContainersWindow, table_state tableState) tableState {
+	switch request := request.(type) {
+	case confirmedDelete:
+		w.handleDelete(w.window_context, &table_state, request.targets)
+	case confirmedKill:
+		w.handleBulkAction(w.window_context, bulkSignal(request.signal), request.targets)
+	case confirmedComposeDown:
+		w.handleComposeDown(w.window_context)
+	case menuSelection:
+		table_state = handleMenuSelection(request, w, table_state)
+	case renameRequest:
+		w.handleRename(w.window_context, request.id, request.new_name)
+	case limitsUpdate:
+		w.handleLimitsUpdate(w.window_context, request)
+	case execRequest:
+		handleExecRequest(request)
+	case runRequest:
+		w.handleRunRequest(w.window_context, request)
+	case composeServiceRequest:
+		w.handleComposeServiceRequest(w.window_context, request)
+	case commandRequest:
+		handleCommandRequest(request)
+	case playRecordingRequest:
+		window.GetScreen().PostEvent(window.NewChangeToCastPlayerEvent(request.path))
+	default:
+		log.Printf("Got unknown request %T", request)
+	}
+	return table_state
+}
@@ -0,0 +1,206 @@
+package containers_window
+
+import (
+	"context"
+	docker "dc-top/docker"
+	"dc-top/docker/compose"
+	"dc-top/gui/view/window"
+	"dc-top/gui/view/window/bar_window"
+	"dc-top/gui/view/window/form_window"
+	"dc-top/utils"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	runImageField = iota
+	runNameField
+	runCommandField
+	runEnvField
+	runPortsField
+	runVolumesField
+	runNetworkField
+	runRestartField
+	runCpusField
+	runMemoryField
+)
+
+var runFormLabels = []string{
+	"Image (Ctrl+O to pick)",
+	"Name",
+	"Command",
+	"Env (KEY=value ...)",
+	"Ports (8080:80 ...)",
+	"Volumes (/host:/container ...)",
+	"Network",
+	"Restart policy",
+	"CPUs (0 = unlimited)",
+	"Memory (e.g. 512m, 0 = unlimited)",
+}
+
+var runFormDefaults = []string{"", "", "", "", "", "", "", "no", "0", "0"}
+
+type runRequest struct {
+	values []string
+}
+
+type composeServiceRequest struct {
+	values []string
+}
+
+func openRunForm(ctx context.Context, values []string) {
+	actions := []window.FormAction{
+		{
+			Key:     tcell.KeyEnter,
+			KeyName: "Enter",
+			Label:   "run",
+			MessageGenerator: func(values []string) interface{} {
+				return runRequest{values: values}
+			},
+		},
+		{
+			Key:     tcell.KeyCtrlP,
+			KeyName: "Ctrl+P",
+			Label:   "docker run command",
+			MessageGenerator: func(values []string) interface{} {
+				if spec, err := parseRunSpec(values); err != nil {
+					bar_window.Err([]rune(err.Error()))
+				} else {
+					window.GetScreen().PostEvent(window.NewChangeToTextEvent("docker run command", []byte(spec.DockerRunCommand())))
+				}
+				return nil
+			},
+			KeepOpen: true,
+		},
+		{
+			Key:     tcell.KeyCtrlY,
+			KeyName: "Ctrl+Y",
+			Label:   "compose service",
+			MessageGenerator: func(values []string) interface{} {
+				return composeServiceRequest{values: values}
+			},
+		},
+		{
+			Key:     tcell.KeyCtrlO,
+			KeyName: "Ctrl+O",
+			Label:   "pick image",
+			MessageGenerator: func([]string) interface{} {
+				openImagePicker(ctx)
+				return nil
+			},
+			KeepOpen: true,
+		},
+	}
+	window.GetScreen().PostEvent(window.NewChangeToFormEvent("Run new container", runFormLabels, values, actions, window.ContainersHolder))
+}
+
+// the chosen image is sent straight to the form, which is the current view once the menu closes
+func openImagePicker(ctx context.Context) {
+	go func() {
+		images, err := docker.ListImages(ctx)
+		if err != nil {
+			bar_window.Err([]rune(fmt.Sprintf("Failed to list images: %s", err)))
+			return
+		}
+		if len(images) == 0 {
+			bar_window.Warn([]rune("There are no local images"))
+			return
+		}
+		items := make([]window.MenuItem, len(images))
+		for i, image := range images {
+			items[i] = window.MenuItem{Label: image, Message: form_window.SetFieldValue{Field: runImageField, Value: image}}
+		}
+		width, height := window.GetScreen().Size()
+		window.GetScreen().PostEvent(window.NewChangeToMenuEvent("Images", items, width/3, height/4, window.Form))
+	}()
+}
+
+func (w *ContainersWindow) handleRunRequest(ctx context.Context, request runRequest) {
+	spec, err := parseRunSpec(request.values)
+	if err != nil {
+		bar_window.Err([]rune(err.Error()))
+		openRunForm(ctx, request.values)
+		return
+	}
+	bar_window.Info([]rune(fmt.Sprintf("Starting a new %s container...", spec.Image)))
+	go func() {
+		id, err := docker.RunContainer(ctx, spec)
+		if err != nil {
+			log.Printf("Got error '%s' when running a new %s container", err, spec.Image)
+			bar_window.Err([]rune(fmt.Sprintf("Failed to run a new %s container", spec.Image)))
+			window.GetScreen().PostEvent(window.NewChangeToErrorEvent([]byte(fmt.Sprintf("Failed to run %s:\n%s", spec.DockerRunCommand(), err))))
+			return
+		}
+		bar_window.Info([]rune(fmt.Sprintf("Started container %.12s", id)))
+	}()
+}
+
+func (w *ContainersWindow) handleComposeServiceRequest(ctx context.Context, request composeServiceRequest) {
+	spec, err := parseRunSpec(request.values)
+	if err != nil {
+		bar_window.Err([]rune(err.Error()))
+		openRunForm(ctx, request.values)
+		return
+	}
+	name, service := spec.ComposeService()
+	if !compose.DcModeEnabled() {
+		snippet, err := compose.ServiceSnippet(name, service)
+		if err != nil {
+			bar_window.Err([]rune(fmt.Sprintf("Failed to generate service: %s", err)))
+			return
+		}
+		window.GetScreen().PostEvent(window.NewChangeToTextEvent("docker-compose service", []byte(snippet)))
+		return
+	}
+	go func() {
+		output, err := compose.AddService(ctx, name, service)
+		if err != nil {
+			bar_window.Err([]rune(fmt.Sprintf("Failed to add service %s: %s", name, err)))
+			if len(output) > 0 {
+				window.GetScreen().PostEvent(window.NewChangeToErrorEvent(output))
+			}
+			return
+		}
+		if err = compose.UpdateContainerFilters(ctx); err != nil {
+			log.Printf("Failed to update filters: '%s", err)
+		}
+		bar_window.Info([]rune(fmt.Sprintf("Added service %s to docker-compose yaml, press Ctrl+U to start it", name)))
+	}()
+}
+
+func parseRunSpec(values []string) (docker.RunSpec, error) {
+	spec := docker.RunSpec{
+		Image:   strings.TrimSpace(values[runImageField]),
+		Name:    strings.TrimSpace(values[runNameField]),
+		Command: utils.SplitArgs(values[runCommandField]),
+		Env:     utils.SplitArgs(values[runEnvField]),
+		Ports:   utils.SplitArgs(values[runPortsField]),
+		Volumes: utils.SplitArgs(values[runVolumesField]),
+		Network: strings.TrimSpace(values[runNetworkField]),
+	}
+	if spec.Image == "" {
+		return spec, fmt.Errorf("an image is required")
+	}
+	for _, env := range spec.Env {
+		if !strings.Contains(env, "=") {
+			return spec, fmt.Errorf("bad env value '%s' (KEY=value)", env)
+		}
+	}
+	var err error
+	if spec.RestartPolicy, spec.MaximumRetryCount, err = parseRestartPolicy(values[runRestartField]); err != nil {
+		return spec, err
+	}
+	cpus, err := strconv.ParseFloat(strings.TrimSpace(values[runCpusField]), 64)
+	if err != nil || cpus < 0 {
+		return spec, fmt.Errorf("bad CPUs value '%s'", values[runCpusField])
+	}
+	spec.NanoCPUs = int64(cpus * 1e9)
+	if spec.Memory, err = parseBytes(values[runMemoryField]); err != nil || spec.Memory < 0 {
+		return spec, fmt.Errorf("bad memory value '%s'", values[runMemoryField])
+	}
+	return spec, nil
+}
@@ -0,0 +1,86 @@
+package containers_window
+
+import (
+	docker "dc-top/docker"
+	"sort"
+)
+
+// selections are shared with the drawer through state copies, so they are never modified in place
+
+func toggleSelection(selected map[string]bool, id string) map[string]bool {
+	new_selection := cloneSelection(selected)
+	if new_selection[id] {
+		delete(new_selection, id)
+	} else {
+		new_selection[id] = true
+	}
+	return new_selection
+}
+
+func selectAll(selected map[string]bool, data []docker.ContainerDatum) map[string]bool {
+	new_selection := cloneSelection(selected)
+	for _, datum := range data {
+		new_selection[datum.ID()] = true
+	}
+	return new_selection
+}
+
+func pruneSelection(selected map[string]bool, data *docker.ContainerData) map[string]bool {
+	new_selection := make(map[string]bool)
+	for id := range selected {
+		if data.Contains(id) {
+			new_selection[id] = true
+		}
+	}
+	return new_selection
+}
+
+func cloneSelection(selected map[string]bool) map[string]bool {
+	new_selection := make(map[string]bool, len(selected))
+	for id := range selected {
+		new_selection[id] = true
+	}
+	return new_selection
+}
+
+func selectedContainers(state *tableState) []docker.ContainerDatum {
+	targets := make([]docker.ContainerDatum, 0, len(state.selected_ids))
+	for _, datum := range state.containers_data.GetData() {
+		if state.selected_ids[datum.ID()] {
+			targets = append(targets, datum)
+		}
+	}
+	sort.SliceStable(targets, func(i, j int) bool { return targets[i].CachedStats().Name < targets[j].CachedStats().Name })
+	return targets
+}
+
+func containerIds(data []docker.ContainerDatum) []string {
+	ids := make([]string, len(data))
+	for i := range data {
+		ids[i] = data[i].ID()
+	}
+	return ids
+}
+
+// projectContainerIds returns the containers of a compose project, even the ones hidden by the search
+func projectContainerIds(state *tableState, project string) []string {
+	ids := make([]string, 0)
+	for _, datum := range state.containers_data.GetData() {
+		if datum.ComposeProject() == project {
+			ids = append(ids, datum.ID())
+		}
+	}
+	return ids
+}
+
+// actionTargets returns the selected containers, or the focused one if nothing is selected
+func actionTargets(state *tableState) []docker.ContainerDatum {
+	if len(state.selected_ids) > 0 {
+		return selectedContainers(state)
+	}
+	index, err := findIndexOfId(state.containers_data.GetData(), state.focused_id)
+	if err != nil {
+		return []docker.ContainerDatum{}
+	}
+	return []docker.ContainerDatum{state.containers_data.GetData()[index]}
+}
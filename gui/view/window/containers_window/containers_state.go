@@ -12,8 +12,10 @@ type tableState struct {
 	window_mode   windowMode
 	keyboard_mode keyboardMode
 	focused_id    string
+	selected_ids  map[string]bool
 	//containers view
 	search_box             elements.TextBox
+	selection_box          elements.TextBox
 	index_of_top_container int
 	table_height           int
 	containers_data        docker.ContainerData
@@ -36,6 +38,7 @@ func handleNewData(new_data *docker.ContainerData, w *ContainersWindow, table_st
 	// log.Printf("Got new data\n")
 	table_state.containers_data = *new_data
 	table_state.filtered_data = table_state.containers_data.Filter(table_state.search_box.Value())
+	table_state.selected_ids = pruneSelection(table_state.selected_ids, new_data)
 	if !new_data.Contains(table_state.focused_id) {
 		table_state.focused_id = ""
 		table_state.window_mode = containers
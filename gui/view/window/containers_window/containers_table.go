@@ -40,6 +40,8 @@ func dockerStatsDrawerGenerator(state tableState, window_width int) (func(x, y i
 		data_table := generateTable(&state, window_width)
 		search_row := state.search_box.Style()
 		search_filter_message := elements.TextDrawer(fmt.Sprintf("Showing only containers containing '%s'", state.search_box.Value()), tcell.StyleDefault.Bold(true))
+		selection_row := state.selection_box.Style()
+		selection_message := elements.TextDrawer(fmt.Sprintf("%d containers selected", len(state.selected_ids)), tcell.StyleDefault.Bold(true))
 		empty_buttom_row := elements.RuneNRepeater('/', 1, tcell.StyleDefault.Foreground(tcell.ColorYellow))
 
 		return func(x, y int) (rune, tcell.Style) {
@@ -49,8 +51,12 @@ func dockerStatsDrawerGenerator(state tableState, window_width int) (func(x, y i
 			if y == state.table_height+2 {
 				if state.keyboard_mode == search {
 					return search_row(x)
+				} else if state.keyboard_mode == selection {
+					return selection_row(x)
 				} else if state.keyboard_mode == regular && state.search_box.Value() != "" {
 					return search_filter_message(x)
+				} else if len(state.selected_ids) > 0 {
+					return selection_message(x)
 				} else {
 					return empty_buttom_row(x)
 				}
@@ -60,6 +66,9 @@ func dockerStatsDrawerGenerator(state tableState, window_width int) (func(x, y i
 				if state.filtered_data[y+state.index_of_top_container-2].IsDeleted() {
 					s = s.Background(tcell.ColorDarkRed)
 				}
+				if state.selected_ids[state.filtered_data[y+state.index_of_top_container-2].ID()] {
+					s = s.Background(tcell.ColorDarkCyan)
+				}
 				if state.focused_id == state.filtered_data[y+state.index_of_top_container-2].ID() {
 					s = s.Background(tcell.ColorDarkBlue)
 				}
@@ -31,6 +31,7 @@ type ContainersWindow struct {
 	data_request_chan       chan tableState
 	draw_queue              chan tableState
 	enable_toggle           chan bool
+	action_chan             chan interface{}
 	//containers view
 	mouse_chan    chan tcell.EventMouse
 	keyboard_chan chan tcell.EventKey
@@ -47,6 +48,7 @@ func NewContainersWindow() ContainersWindow {
 		resize_chan:             make(chan interface{}),
 		draw_queue:              make(chan tableState),
 		enable_toggle:           make(chan bool),
+		action_chan:             make(chan interface{}),
 		new_container_data_chan: make(chan docker.ContainerData),
 		//containers view
 		mouse_chan:        make(chan tcell.EventMouse),
@@ -101,6 +103,8 @@ func (w *ContainersWindow) HandleEvent(ev interface{}, sender window.WindowType)
 			TotalMemUsage:       total_mem_usage,
 		}
 		window.GetScreen().PostEvent(window.NewMessageEvent(sender, window.ContainersHolder, summary))
+	case confirmedDelete, confirmedKill, confirmedComposeDown, menuSelection, renameRequest, limitsUpdate, runRequest, composeServiceRequest, execRequest, playRecordingRequest, commandRequest:
+		w.action_chan <- ev
 	default:
 		log.Fatal("Got unknown event in holder", ev)
 	}
@@ -137,6 +141,13 @@ func (w *ContainersWindow) main() {
 			tcell.StyleDefault,
 			tcell.StyleDefault.Background(tcell.ColorWhite).Foreground(tcell.ColorBlack),
 			true),
+		selection_box: elements.NewTextBox(
+			elements.TextDrawer(" select: ", tcell.StyleDefault.Foreground(tcell.ColorYellow)),
+			9,
+			tcell.StyleDefault,
+			tcell.StyleDefault.Background(tcell.ColorWhite).Foreground(tcell.ColorBlack),
+			true),
+		selected_ids:           make(map[string]bool),
 		index_of_top_container: 0,
 		table_height:           calcTableHeight(y1, y2),
 		main_sort_type:         docker.State,
@@ -169,6 +180,9 @@ func (w *ContainersWindow) main() {
 			state = handleMouseEvent(&mouse_event, w, state)
 			state.containers_data = state.containers_data.GetSortedData(state.main_sort_type, state.secondary_sort_type, state.is_reverse_sort)
 			state.filtered_data = state.containers_data.Filter(state.search_box.Value())
+		case request := <-w.action_chan:
+			state = handleRequest(request, w, state)
+			state.filtered_data = state.containers_data.Filter(state.search_box.Value())
 		case keyboard_event := <-w.keyboard_chan:
 			state, err = handleKeyboardEvent(&keyboard_event, w, state)
 			window.ExitIfErr(err)
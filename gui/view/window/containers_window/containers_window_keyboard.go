@@ -16,6 +16,7 @@ type keyboardMode uint8
 const (
 	regular keyboardMode = iota
 	search
+	selection
 )
 
 func handleKeyboardEvent(ev *tcell.EventKey, w *ContainersWindow, table_state tableState) (tableState, error) {
@@ -26,6 +27,8 @@ func handleKeyboardEvent(ev *tcell.EventKey, w *ContainersWindow, table_state ta
 		}
 	} else if table_state.keyboard_mode == search {
 		table_state.searchKeyPress(ev, w)
+	} else if table_state.keyboard_mode == selection {
+		table_state.selectionKeyPress(ev, w)
 	} else {
 		log.Fatal("Unknown keyboard mode", table_state.keyboard_mode)
 	}
@@ -49,13 +52,13 @@ func (state *tableState) regularKeyPress(ev *tcell.EventKey, w *ContainersWindow
 		}
 	case tcell.KeyDelete:
 		state.window_mode = containers
-		if state.focused_id != "" {
-			err := w.handleDelete(w.window_context, state)
-			if err != nil {
-				return err
-			}
+		if areMutationsAllowed() {
+			requestDeleteConfirmation(actionTargets(state))
 		}
 	case tcell.KeyCtrlW:
+		if !areMutationsAllowed() {
+			break
+		}
 		if compose.DcModeEnabled() {
 			if !compose.ValidateYaml(w.window_context) {
 				bar_window.Err([]rune("docker compose yaml syntax is invalid"))
@@ -75,6 +78,9 @@ func (state *tableState) regularKeyPress(ev *tcell.EventKey, w *ContainersWindow
 			bar_window.Err([]rune("dc mode is disabled"))
 		}
 	case tcell.KeyCtrlU:
+		if !areMutationsAllowed() {
+			break
+		}
 		if compose.DcModeEnabled() {
 			if !compose.ValidateYaml(w.window_context) {
 				bar_window.Err([]rune("docker compose yaml syntax is invalid"))
@@ -94,54 +100,118 @@ func (state *tableState) regularKeyPress(ev *tcell.EventKey, w *ContainersWindow
 			bar_window.Err([]rune("dc mode is disabled"))
 		}
 	case tcell.KeyCtrlD:
+		if !areMutationsAllowed() {
+			break
+		}
 		if compose.DcModeEnabled() {
 			if !compose.ValidateYaml(w.window_context) {
 				bar_window.Err([]rune("docker compose yaml syntax is invalid"))
 				output, _ := compose.Config(w.window_context)
 				window.GetScreen().PostEvent(window.NewChangeToErrorEvent(output))
 			} else {
-				bar_window.Info([]rune("Removing docker compose..."))
-				go func() {
-					if msg, err := compose.Down(w.window_context); err != nil {
-						bar_window.Err([]rune("Failed to remove docker compose"))
-						full_message := "Got error when removing docker-compose:\n" + string(msg)
-						window.GetScreen().PostEvent(window.NewChangeToErrorEvent([]byte(full_message)))
-					}
-				}()
+				requestComposeDownConfirmation()
 			}
 		} else {
 			bar_window.Err([]rune("dc mode is disabled"))
 		}
 	case tcell.KeyCtrlP:
-		if state.focused_id != "" {
+		if !areMutationsAllowed() {
+			break
+		}
+		if targets := selectedContainers(state); len(targets) > 0 {
+			w.handleBulkAction(w.window_context, bulkPause(targets), targets)
+		} else if state.focused_id != "" {
 			w.handlePause(w.window_context, state.focused_id)
 		}
 	case tcell.KeyCtrlR:
-		if state.focused_id != "" {
+		if !areMutationsAllowed() {
+			break
+		}
+		if targets := selectedContainers(state); len(targets) > 0 {
+			w.handleBulkAction(w.window_context, bulkRestart, targets)
+		} else if state.focused_id != "" {
 			w.handleRestart(w.window_context, state.focused_id)
 		}
 	case tcell.KeyCtrlS:
-		if state.focused_id != "" {
+		if !areMutationsAllowed() {
+			break
+		}
+		if targets := selectedContainers(state); len(targets) > 0 {
+			w.handleBulkAction(w.window_context, bulkStop, targets)
+		} else if state.focused_id != "" {
 			w.handleStop(w.window_context, state.focused_id)
 		}
 	case tcell.KeyRune:
 		screen := window.GetScreen()
 		switch ev.Rune() {
 		case 'l':
-			if state.focused_id != "" {
-				screen.PostEvent(window.NewChangeToLogsWindowEvent(state.focused_id))
+			if targets := actionTargets(state); len(targets) > 0 {
+				screen.PostEvent(window.NewChangeToLogsWindowEvent(containerIds(targets)...))
+			}
+		case 'M':
+			if len(state.filtered_data) > 0 {
+				screen.PostEvent(window.NewChangeToLogsWindowEvent(containerIds(state.filtered_data)...))
 			}
 		case 'h':
 			screen.PostEvent(window.NewChangeToMainHelpEvent())
-		case 'e':
-			if state.focused_id != "" {
+		case 'e', 'E', 'A':
+			if state.focused_id != "" && areMutationsAllowed() {
 				index, err := findIndexOfId(state.containers_data.GetData(), state.focused_id)
-				if err != nil || state.containers_data.GetData()[index].State() != "running" {
+				if err != nil {
+					break
+				}
+				datum := state.containers_data.GetData()[index]
+				if datum.State() != "running" {
+					bar_window.Err([]rune(fmt.Sprintf("Container %s isn't running", datum.CachedStats().Name)))
+				} else if ev.Rune() == 'E' {
+					openExecForm(datum)
+				} else if ev.Rune() == 'A' {
+					screen.PostEvent(window.NewChangeToAttachEvent(datum.ID()))
+				} else {
+					openShell(datum)
+				}
+			}
+		case 'd':
+			if state.focused_id != "" && areMutationsAllowed() {
+				index, err := findIndexOfId(state.containers_data.GetData(), state.focused_id)
+				if err != nil {
+					break
+				}
+				if state.containers_data.GetData()[index].State() != "running" {
 					bar_window.Err([]rune(fmt.Sprintf("Container %s isn't running", state.containers_data.GetData()[index].CachedStats().Name)))
 				} else {
-					screen.PostEvent(window.NewChangeToContainerShellEvent(state.focused_id))
+					screen.PostEvent(window.NewChangeToDebugShellEvent(state.focused_id))
+				}
+			}
+		case 'F':
+			if state.focused_id != "" {
+				screen.PostEvent(window.NewChangeToFileBrowserEvent(state.focused_id))
+			}
+		case 'p':
+			if state.focused_id != "" {
+				index, err := findIndexOfId(state.containers_data.GetData(), state.focused_id)
+				if err != nil {
+					break
+				}
+				datum := state.containers_data.GetData()[index]
+				if datum.State() != "running" && datum.State() != "paused" {
+					bar_window.Err([]rune(fmt.Sprintf("Container %s isn't running", datum.CachedStats().Name)))
+				} else {
+					screen.PostEvent(window.NewChangeToTopEvent(datum.ID()))
 				}
 			}
+		case 'D':
+			if state.focused_id != "" {
+				screen.PostEvent(window.NewChangeToDiffEvent(state.focused_id))
+			}
+		case 'T':
+			screen.PostEvent(window.NewResumeSubshellsEvent())
+		case 'P':
+			openRecordingsMenu()
+		case 'x':
+			if areMutationsAllowed() {
+				openCommandPrompt(actionTargets(state))
+			}
 		case 'i':
 			if state.window_mode == containers {
 				_, err := findIndexOfId(state.containers_data.GetData(), state.focused_id)
@@ -154,6 +224,9 @@ func (state *tableState) regularKeyPress(ev *tcell.EventKey, w *ContainersWindow
 			}
 			log.Println("Toggling inspect mode")
 		case 'v':
+			if !areMutationsAllowed() {
+				break
+			}
 			if compose.DcModeEnabled() {
 				window.GetScreen().PostEvent(window.NewChangeToFileEdittorEvent(compose.DcYamlPath()))
 			} else {
@@ -179,6 +252,44 @@ func (state *tableState) regularKeyPress(ev *tcell.EventKey, w *ContainersWindow
 			state.keyboard_mode = search
 		case '!':
 			state.is_reverse_sort = !state.is_reverse_sort
+		case ' ':
+			if state.window_mode == containers && state.focused_id != "" {
+				state.selected_ids = toggleSelection(state.selected_ids, state.focused_id)
+			}
+		case 'a':
+			state.selected_ids = selectAll(state.selected_ids, state.filtered_data)
+			bar_window.Info([]rune(fmt.Sprintf("Selected %d containers", len(state.selected_ids))))
+		case 's':
+			state.selection_box.Reset()
+			bar_window.Info([]rune("Select containers containing..."))
+			state.keyboard_mode = selection
+		case 'u':
+			state.selected_ids = make(map[string]bool)
+			bar_window.Info([]rune("Cleared selection"))
+		case 'm':
+			if state.window_mode == containers && state.focused_id != "" {
+				x, y := focusedRowPosition(w, state)
+				openContainerMenu(state, state.focused_id, x, y)
+			}
+		case 'S':
+			if areMutationsAllowed() {
+				w.handleGracefulStop(w.window_context, actionTargets(state))
+			}
+		case 'L':
+			if state.focused_id != "" && areMutationsAllowed() {
+				index, err := findIndexOfId(state.containers_data.GetData(), state.focused_id)
+				if err == nil {
+					openLimitsForm(w.window_context, state.containers_data.GetData()[index])
+				}
+			}
+		case 'r':
+			if areMutationsAllowed() {
+				openRunForm(w.window_context, runFormDefaults)
+			}
+		case 'K':
+			if areMutationsAllowed() {
+				requestKillConfirmation(actionTargets(state), "SIGKILL")
+			}
 		case 'f':
 			if compose.DcModeEnabled() {
 				state.is_filter_enabled = !state.is_filter_enabled
@@ -224,3 +335,23 @@ func (state *tableState) searchKeyPress(ev *tcell.EventKey, w *ContainersWindow)
 	state.filtered_data = state.containers_data.Filter(state.search_box.Value())
 	restartIndex(state)
 }
+
+func (state *tableState) selectionKeyPress(ev *tcell.EventKey, w *ContainersWindow) {
+	key := ev.Key()
+	switch key {
+	case tcell.KeyEnter:
+		state.keyboard_mode = regular
+		if state.selection_box.Value() != "" {
+			state.selected_ids = selectAll(state.selected_ids, state.containers_data.Filter(state.selection_box.Value()))
+			bar_window.Info([]rune(fmt.Sprintf("Selected %d containers", len(state.selected_ids))))
+		}
+	case tcell.KeyEscape:
+		state.keyboard_mode = regular
+		state.selection_box.Reset()
+	case tcell.KeyCtrlD:
+		state.keyboard_mode = regular
+		state.selection_box.Reset()
+	default:
+		state.selection_box.HandleKey(ev)
+	}
+}
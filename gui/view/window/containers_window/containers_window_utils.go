@@ -1,7 +1,9 @@
 package containers_window
 
 import (
+	"dc-top/config"
 	docker "dc-top/docker"
+	"dc-top/gui/view/window/bar_window"
 	"errors"
 )
 
@@ -13,3 +15,11 @@ func findIndexOfId(data []docker.ContainerDatum, id string) (int, error) {
 	}
 	return -1, errors.New("index of id")
 }
+
+func areMutationsAllowed() bool {
+	if config.IsReadOnly() {
+		bar_window.Err([]rune(config.ErrReadOnly.Error()))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,180 @@
+package diff_window
+
+import (
+	"dc-top/docker"
+	"dc-top/gui/elements"
+	"dc-top/utils"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type diffRow struct {
+	node   *diffNode
+	depth  int
+	counts changeCounts
+}
+
+type diffState struct {
+	root     *diffNode
+	counts   map[*diffNode]changeCounts
+	expanded map[string]bool
+	filter   diffFilter
+
+	rows    []diffRow
+	focused int
+	top_row int
+	status  string
+	loading bool
+
+	search_box elements.TextBox
+	is_typing  bool
+}
+
+func newDiffState() *diffState {
+	return &diffState{
+		root:     &diffNode{path: "/"},
+		expanded: make(map[string]bool),
+		search_box: elements.NewTextBox(
+			elements.TextDrawer("/", tcell.StyleDefault.Foreground(tcell.ColorYellow)),
+			1,
+			tcell.StyleDefault,
+			tcell.StyleDefault.Background(tcell.ColorWhite).Foreground(tcell.ColorBlack),
+			true),
+	}
+}
+
+// setTree shows a new diff, everything is expanded except added directories, whose contents are all new
+func (state *diffState) setTree(root *diffNode) {
+	state.root = root
+	state.expanded = make(map[string]bool)
+	var expand func(node *diffNode)
+	expand = func(node *diffNode) {
+		if node.is_changed && node.kind == docker.ChangeAdded {
+			return
+		}
+		state.expanded[node.path] = true
+		for _, child := range node.children {
+			expand(child)
+		}
+	}
+	expand(root)
+	state.rebuildRows()
+}
+
+func (state *diffState) setAllExpanded(is_expanded bool) {
+	var walk func(node *diffNode)
+	walk = func(node *diffNode) {
+		if len(node.children) > 0 {
+			state.expanded[node.path] = is_expanded
+		}
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	for _, child := range state.root.children {
+		walk(child)
+	}
+	state.rebuildRows()
+}
+
+// rebuildRows flattens the expanded nodes with changes passing the filter, keeping the focus on the same path
+func (state *diffState) rebuildRows() {
+	focused_path := ""
+	if node, ok := state.focusedNode(); ok {
+		focused_path = node.path
+	}
+	state.counts = make(map[*diffNode]changeCounts)
+	countChanges(state.root, &state.filter, state.counts)
+	state.rows = state.rows[:0]
+	state.appendRows(state.root, 0)
+	state.focused = 0
+	for i, row := range state.rows {
+		if row.node.path == focused_path {
+			state.focused = i
+			break
+		}
+	}
+}
+
+func (state *diffState) appendRows(node *diffNode, depth int) {
+	for _, child := range node.children {
+		counts := state.counts[child]
+		if counts.total() == 0 {
+			continue
+		}
+		state.rows = append(state.rows, diffRow{child, depth, counts})
+		// matches of a text filter are shown even in collapsed directories
+		if state.expanded[child.path] || state.filter.text != "" {
+			state.appendRows(child, depth+1)
+		}
+	}
+}
+
+func (state *diffState) focusedNode() (*diffNode, bool) {
+	if state.focused < 0 || state.focused >= len(state.rows) {
+		return nil, false
+	}
+	return state.rows[state.focused].node, true
+}
+
+func (state *diffState) moveFocus(delta int) {
+	state.focused = utils.Min(utils.Max(state.focused+delta, 0), utils.Max(len(state.rows)-1, 0))
+}
+
+func (state *diffState) setFocusedExpanded(is_expanded bool) {
+	node, ok := state.focusedNode()
+	if !ok || len(node.children) == 0 {
+		return
+	}
+	if !is_expanded && !state.expanded[node.path] {
+		// collapsing a collapsed directory moves to its parent
+		for i := state.focused - 1; i >= 0; i-- {
+			if state.rows[i].depth < state.rows[state.focused].depth {
+				state.focused = i
+				return
+			}
+		}
+		return
+	}
+	state.expanded[node.path] = is_expanded
+	state.rebuildRows()
+}
+
+func (state *diffState) toggleKind(kind docker.ChangeKind) {
+	state.filter.hidden_kinds[kind] = !state.filter.hidden_kinds[kind]
+	state.rebuildRows()
+}
+
+var kindStyles = map[docker.ChangeKind]tcell.Style{
+	docker.ChangeAdded:    tcell.StyleDefault.Foreground(tcell.ColorGreen),
+	docker.ChangeModified: tcell.StyleDefault.Foreground(tcell.ColorYellow),
+	docker.ChangeDeleted:  tcell.StyleDefault.Foreground(tcell.ColorRed),
+}
+
+func (state *diffState) formatRow(row diffRow) ([]rune, tcell.Style) {
+	kind, style := " ", tcell.StyleDefault
+	if row.node.is_changed {
+		kind, style = row.node.kind.String(), kindStyles[row.node.kind]
+	}
+	marker := "  "
+	summary := ""
+	if len(row.node.children) > 0 {
+		marker = "▾ "
+		if !state.expanded[row.node.path] && state.filter.text == "" {
+			marker = "▸ "
+			summary = "  " + formatCounts(row.counts)
+		}
+	}
+	name := row.node.name()
+	if row.depth == 0 {
+		name = row.node.path
+	}
+	return []rune(fmt.Sprintf(" %s %s%s%s%s", kind, strings.Repeat("  ", row.depth), marker, name, summary)), style
+}
+
+func formatCounts(counts changeCounts) string {
+	return fmt.Sprintf("(%d added, %d changed, %d deleted)",
+		counts[docker.ChangeAdded], counts[docker.ChangeModified], counts[docker.ChangeDeleted])
+}
@@ -0,0 +1,75 @@
+package diff_window
+
+import (
+	"dc-top/docker"
+	"path"
+	"strings"
+)
+
+// diffNode is a path of the diff, directories that are only parents of changes have is_changed unset
+type diffNode struct {
+	path       string
+	kind       docker.ChangeKind
+	is_changed bool
+	children   []*diffNode
+}
+
+func (node *diffNode) name() string {
+	return path.Base(node.path)
+}
+
+// buildTree arranges the changes, sorted by path, as a tree rooted at "/"
+func buildTree(changes []docker.ContainerChange) *diffNode {
+	root := &diffNode{path: "/"}
+	nodes := map[string]*diffNode{"/": root}
+	var getNode func(node_path string) *diffNode
+	getNode = func(node_path string) *diffNode {
+		if node, ok := nodes[node_path]; ok {
+			return node
+		}
+		node := &diffNode{path: node_path}
+		parent := getNode(path.Dir(node_path))
+		parent.children = append(parent.children, node)
+		nodes[node_path] = node
+		return node
+	}
+	for _, change := range changes {
+		node := getNode(path.Clean("/" + change.Path))
+		node.kind = change.Kind
+		node.is_changed = true
+	}
+	return root
+}
+
+// changeCounts is the number of changes of each kind, indexed by docker.ChangeKind
+type changeCounts [3]int
+
+func (counts changeCounts) total() int {
+	return counts[docker.ChangeModified] + counts[docker.ChangeAdded] + counts[docker.ChangeDeleted]
+}
+
+type diffFilter struct {
+	hidden_kinds [3]bool
+	text         string
+}
+
+func (filter *diffFilter) matches(node *diffNode) bool {
+	return node.is_changed && !filter.hidden_kinds[node.kind] &&
+		(filter.text == "" || strings.Contains(strings.ToLower(node.path), strings.ToLower(filter.text)))
+}
+
+// countChanges counts the changes under every node, including itself, that pass the filter
+func countChanges(node *diffNode, filter *diffFilter, counts map[*diffNode]changeCounts) changeCounts {
+	var node_counts changeCounts
+	if filter.matches(node) {
+		node_counts[node.kind]++
+	}
+	for _, child := range node.children {
+		child_counts := countChanges(child, filter, counts)
+		for kind := range node_counts {
+			node_counts[kind] += child_counts[kind]
+		}
+	}
+	counts[node] = node_counts
+	return node_counts
+}
@@ -0,0 +1,300 @@
+package diff_window
+
+import (
+	"context"
+	"dc-top/docker"
+	"dc-top/gui/view/window"
+	"dc-top/utils"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// DiffWindow lists what a container added, changed and deleted in its writable layer
+type DiffWindow struct {
+	window_ctx    context.Context
+	window_cancel context.CancelFunc
+
+	id   string
+	name string
+
+	dimensions_generator func() window.Dimensions
+	keyboard_ch          chan tcell.EventKey
+	resize_ch            chan interface{}
+	enable_toggle        chan bool
+}
+
+func NewDiffWindow(id string) DiffWindow {
+	return DiffWindow{
+		id: id,
+		dimensions_generator: func() window.Dimensions {
+			w, h := window.GetScreen().Size()
+			return window.NewDimensions(0, 0, w-1, h-1, false)
+		},
+		keyboard_ch:   make(chan tcell.EventKey),
+		resize_ch:     make(chan interface{}),
+		enable_toggle: make(chan bool),
+	}
+}
+
+func (w *DiffWindow) Open(view_ctx context.Context) {
+	w.window_ctx, w.window_cancel = context.WithCancel(view_ctx)
+	w.name = strings.TrimPrefix(docker.InspectContainerNoPanic(w.window_ctx, w.id).Name, "/")
+	go w.main()
+}
+
+func (w *DiffWindow) Resize() {
+	select {
+	case w.resize_ch <- nil:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *DiffWindow) KeyPress(ev tcell.EventKey) {
+	select {
+	case w.keyboard_ch <- ev:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *DiffWindow) MousePress(_ tcell.EventMouse) {}
+
+func (w *DiffWindow) HandleEvent(interface{}, window.WindowType) (interface{}, error) {
+	panic(1)
+}
+
+func (w *DiffWindow) Disable() {
+	log.Printf("Disable DiffWindow...")
+	select {
+	case w.enable_toggle <- false:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *DiffWindow) Enable() {
+	log.Printf("Enable DiffWindow...")
+	select {
+	case w.enable_toggle <- true:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *DiffWindow) Close() {
+	w.window_cancel()
+}
+
+type diffLoaded struct {
+	changes []docker.ContainerChange
+	err     error
+}
+
+func (w *DiffWindow) main() {
+	window.GetScreen().Clear()
+	state := newDiffState()
+	results_ch := make(chan diffLoaded)
+	is_enabled := true
+	w.load(state, results_ch)
+	for {
+		if is_enabled {
+			w.draw(state)
+		}
+		select {
+		case ev := <-w.keyboard_ch:
+			if w.handleKeyPress(state, &ev, results_ch) {
+				return
+			}
+		case result := <-results_ch:
+			state.loading = false
+			if result.err != nil {
+				state.status = fmt.Sprintf("Failed to diff %s: %s", w.name, result.err)
+			} else {
+				state.status = ""
+				state.setTree(buildTree(result.changes))
+			}
+		case <-w.resize_ch:
+			window.GetScreen().Clear()
+		case is_enabled = <-w.enable_toggle:
+		case <-w.window_ctx.Done():
+			return
+		}
+	}
+}
+
+// load diffs in the background, the engine compares the whole writable layer with the image which can take a while
+func (w *DiffWindow) load(state *diffState, results_ch chan<- diffLoaded) {
+	if state.loading {
+		return
+	}
+	state.loading = true
+	state.status = "Comparing the container's filesystem with its image..."
+	go func() {
+		changes, err := docker.ContainerChanges(w.window_ctx, w.id)
+		select {
+		case results_ch <- diffLoaded{changes, err}:
+		case <-w.window_ctx.Done():
+		}
+	}()
+}
+
+// handleKeyPress returns true when the window is closed
+func (w *DiffWindow) handleKeyPress(state *diffState, ev *tcell.EventKey, results_ch chan<- diffLoaded) bool {
+	if state.is_typing {
+		switch ev.Key() {
+		case tcell.KeyEnter:
+			state.is_typing = false
+		case tcell.KeyEscape:
+			state.is_typing = false
+			state.search_box.Reset()
+		default:
+			state.search_box.HandleKey(ev)
+		}
+		state.filter.text = state.search_box.Value()
+		state.rebuildRows()
+		return false
+	}
+	page := w.pageHeight()
+	switch ev.Key() {
+	case tcell.KeyUp:
+		state.moveFocus(-1)
+	case tcell.KeyDown:
+		state.moveFocus(1)
+	case tcell.KeyPgUp:
+		state.moveFocus(-page)
+	case tcell.KeyPgDn:
+		state.moveFocus(page)
+	case tcell.KeyHome:
+		state.moveFocus(-len(state.rows))
+	case tcell.KeyEnd:
+		state.moveFocus(len(state.rows))
+	case tcell.KeyRight:
+		state.setFocusedExpanded(true)
+	case tcell.KeyLeft:
+		state.setFocusedExpanded(false)
+	case tcell.KeyEnter:
+		if node, ok := state.focusedNode(); ok {
+			state.setFocusedExpanded(!state.expanded[node.path])
+		}
+	case tcell.KeyEscape:
+		if state.filter.text != "" {
+			state.search_box.Reset()
+			state.filter.text = ""
+			state.rebuildRows()
+			return false
+		}
+		w.quit()
+		return true
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'q':
+			w.quit()
+			return true
+		case 'g':
+			state.moveFocus(-len(state.rows))
+		case 'G':
+			state.moveFocus(len(state.rows))
+		case 'a':
+			state.toggleKind(docker.ChangeAdded)
+		case 'c':
+			state.toggleKind(docker.ChangeModified)
+		case 'd':
+			state.toggleKind(docker.ChangeDeleted)
+		case '+':
+			state.setAllExpanded(true)
+		case '-':
+			state.setAllExpanded(false)
+		case '/':
+			state.is_typing = true
+		case 'r':
+			w.load(state, results_ch)
+		}
+	}
+	return false
+}
+
+func (w *DiffWindow) quit() {
+	w.window_cancel()
+	window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+}
+
+func (w *DiffWindow) pageHeight() int {
+	dimensions := w.dimensions_generator()
+	return window.Height(&dimensions) - 2
+}
+
+func (w *DiffWindow) draw(state *diffState) {
+	dimensions := w.dimensions_generator()
+	height := window.Height(&dimensions)
+	page := utils.Max(height-2, 1)
+	if state.focused < state.top_row {
+		state.top_row = state.focused
+	} else if state.focused >= state.top_row+page {
+		state.top_row = state.focused - page + 1
+	}
+	state.top_row = utils.Max(utils.Min(state.top_row, len(state.rows)-page), 0)
+
+	header := []rune(fmt.Sprintf(" Changes of %s since it was created: %s", w.name, formatCounts(state.counts[state.root])))
+	status := []rune(" " + state.status)
+	if state.status == "" {
+		status = []rune(" " + w.filterStatus(state) + "  (a/c/d toggle added/changed/deleted, / filter, +/- expand/collapse all, r refresh, q close)")
+	}
+	search := state.search_box.Style()
+	type line struct {
+		text  []rune
+		style tcell.Style
+	}
+	lines := make([]line, page)
+	for y := range lines {
+		if index := state.top_row + y; index < len(state.rows) {
+			lines[y].text, lines[y].style = state.formatRow(state.rows[index])
+		}
+	}
+	window.DrawContents(&dimensions, func(x, y int) (rune, tcell.Style) {
+		switch {
+		case y == 0:
+			if x < len(header) {
+				return header[x], tcell.StyleDefault.Bold(true)
+			}
+			return ' ', tcell.StyleDefault
+		case y == height-1:
+			if state.is_typing {
+				return search(x)
+			}
+			if x < len(status) {
+				return status[x], tcell.StyleDefault.Foreground(tcell.ColorYellow)
+			}
+			return ' ', tcell.StyleDefault
+		}
+		index := state.top_row + y - 1
+		if index >= len(state.rows) {
+			return ' ', tcell.StyleDefault
+		}
+		style := lines[y-1].style
+		if index == state.focused {
+			style = style.Reverse(true)
+		}
+		if x < len(lines[y-1].text) {
+			return lines[y-1].text[x], style
+		}
+		return ' ', style
+	})
+	window.GetScreen().Show()
+}
+
+func (w *DiffWindow) filterStatus(state *diffState) string {
+	var shown []string
+	for _, kind := range []docker.ChangeKind{docker.ChangeAdded, docker.ChangeModified, docker.ChangeDeleted} {
+		if !state.filter.hidden_kinds[kind] {
+			shown = append(shown, kind.String())
+		}
+	}
+	status := fmt.Sprintf("showing [%s]", strings.Join(shown, ""))
+	if state.filter.text != "" {
+		status += fmt.Sprintf(" matching '%s'", state.filter.text)
+	}
+	if len(state.rows) == 0 && !state.loading {
+		status += ", no changes"
+	}
+	return status
+}
@@ -1,7 +1,6 @@
 package edittor_window
 
 import (
-	"os"
 	"strings"
 )
 
@@ -17,11 +16,6 @@ func contentsEquals(content1 []string, content2 []string) bool {
 	return true
 }
 
-func writeNewContent(content []string, file *os.File) error {
-	new_raw_content := []byte(strings.Join(content, "\n"))
-	err := os.WriteFile(file.Name(), new_raw_content, 0664)
-	if err != nil {
-		return err
-	}
-	return nil
+func joinContent(content []string) []byte {
+	return []byte(strings.Join(content, "\n"))
 }
@@ -0,0 +1,103 @@
+package edittor_window
+
+import (
+	"context"
+	"dc-top/docker"
+	"dc-top/docker/compose"
+	"dc-top/gui/view/window"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+)
+
+// EdittedFile is where the edittor reads its content from and writes it back to
+type EdittedFile interface {
+	Name() string
+	Read(ctx context.Context) ([]byte, error)
+	// Save writes the new content, the edittor stays open if it fails
+	Save(ctx context.Context, content []byte) error
+	Close()
+}
+
+type composeFile struct {
+	file *os.File
+}
+
+// NewComposeFile edits the docker-compose yaml, which is validated before it's saved
+func NewComposeFile(file *os.File) EdittedFile {
+	return &composeFile{file: file}
+}
+
+func (f *composeFile) Name() string {
+	return f.file.Name()
+}
+
+func (f *composeFile) Read(context.Context) ([]byte, error) {
+	return ioutil.ReadFile(f.file.Name())
+}
+
+func (f *composeFile) Save(ctx context.Context, content []byte) error {
+	compose.CreateBackupYaml()
+	if err := os.WriteFile(f.file.Name(), content, 0664); err != nil {
+		return fmt.Errorf("Got error '%s' while writing to file", err)
+	}
+	if !compose.ValidateYaml(ctx) {
+		output, _ := compose.Config(ctx)
+		compose.RestoreFromBackup()
+		window.GetScreen().PostEvent(window.NewChangeToErrorEvent(output))
+		return errors.New("docker-compose yaml contains errors")
+	}
+
+	// Sometimes updating filters fails for unknown reasons so i retry
+	for i := 0; i < 3; i++ {
+		err := compose.UpdateContainerFilters(ctx)
+		if err == nil {
+			break
+		} else {
+			log.Printf("Failed to update filters: '%s", err)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	window.GetScreen().PostEvent(window.NewUpdateDockerCompose())
+	return nil
+}
+
+func (f *composeFile) Close() {
+	f.file.Close()
+}
+
+type containerFile struct {
+	id   string
+	path string
+	file docker.EditableFile
+}
+
+// NewContainerFile edits a file inside a container through the archive API, keeping its owner and mode
+func NewContainerFile(id string, path string) EdittedFile {
+	return &containerFile{id: id, path: path}
+}
+
+func (f *containerFile) Name() string {
+	return f.path
+}
+
+func (f *containerFile) Read(ctx context.Context) ([]byte, error) {
+	file, content, err := docker.ReadEditableFile(ctx, f.id, f.path)
+	if err != nil {
+		return nil, err
+	}
+	f.file = file
+	return content, nil
+}
+
+func (f *containerFile) Save(ctx context.Context, content []byte) error {
+	if err := docker.WriteContainerFile(ctx, f.id, f.file, content); err != nil {
+		return fmt.Errorf("Failed to write %s: %s", f.path, err)
+	}
+	return nil
+}
+
+func (f *containerFile) Close() {}
@@ -5,9 +5,7 @@ import (
 	"dc-top/gui/elements"
 	"dc-top/gui/view/window"
 	"dc-top/gui/view/window/bar_window"
-	"io/ioutil"
 	"log"
-	"os"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
@@ -15,7 +13,7 @@ import (
 
 type edittorState struct {
 	ctx  context.Context
-	file *os.File
+	file EdittedFile
 
 	is_enabled       bool
 	keyboard_mode    _KeyboardMode
@@ -46,9 +44,9 @@ func (edittor_window *EdittorWindow) main() error {
 		search_box:    elements.NewTextBox(elements.TextDrawer("/ ", tcell.StyleDefault.Foreground(tcell.ColorYellow)), 2, tcell.StyleDefault, tcell.StyleDefault, true),
 	}
 
-	raw_bytes, err := ioutil.ReadFile(edittor_window.file.Name())
+	raw_bytes, err := edittor_window.file.Read(edittor_window.window_context)
 	if err != nil {
-		log.Printf("Failed to read file %s", edittor_window.file.Name())
+		log.Printf("Failed to read file %s: %s", edittor_window.file.Name(), err)
 		return err
 	}
 
@@ -6,7 +6,6 @@ import (
 	"dc-top/gui/view/window/bar_window"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/gdamore/tcell/v2"
 	"golang.org/x/sync/semaphore"
@@ -18,14 +17,14 @@ type EdittorWindow struct {
 	dimensions_generator func() window.Dimensions
 	drawer_semaphore     *semaphore.Weighted
 
-	file *os.File
+	file EdittedFile
 
 	resize_chan   chan interface{}
 	enable_toggle chan bool
 	keyboard_chan chan *tcell.EventKey
 }
 
-func NewEdittorWindow(file *os.File) EdittorWindow {
+func NewEdittorWindow(file EdittedFile) EdittorWindow {
 
 	return EdittorWindow{
 		drawer_semaphore: semaphore.NewWeighted(1),
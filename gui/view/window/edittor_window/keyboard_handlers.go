@@ -1,13 +1,10 @@
 package edittor_window
 
 import (
-	"dc-top/docker/compose"
 	"dc-top/gui/view/window"
 	"dc-top/gui/view/window/bar_window"
 	"fmt"
-	"log"
 	"strings"
-	"time"
 
 	"github.com/gdamore/tcell/v2"
 )
@@ -189,31 +186,10 @@ func (state *edittorState) handleRegularKey(ev *tcell.EventKey) {
 
 func (state *edittorState) finalizeEdittor() {
 	if !contentsEquals(state.content, state.original_content) {
-		compose.CreateBackupYaml()
-		err := writeNewContent(state.content, state.file)
-		if err != nil {
-			bar_window.Err([]rune(fmt.Sprintf("Got error '%s' while writing to file", err.Error())))
+		if err := state.file.Save(state.ctx, joinContent(state.content)); err != nil {
+			bar_window.Err([]rune(err.Error()))
 			return
 		}
-		if !compose.ValidateYaml(state.ctx) {
-			output, _ := compose.Config(state.ctx)
-			compose.RestoreFromBackup()
-			bar_window.Err([]rune("docker-compose yaml contains errors"))
-			window.GetScreen().PostEvent(window.NewChangeToErrorEvent(output))
-			return
-		}
-
-		// Sometimes updating filters fails for unknown reasons so i retry
-		for i := 0; i < 3; i++ {
-			err = compose.UpdateContainerFilters(state.ctx)
-			if err == nil {
-				break
-			} else {
-				log.Printf("Failed to update filters: '%s", err)
-				time.Sleep(10 * time.Millisecond)
-			}
-		}
-		window.GetScreen().PostEvent(window.NewUpdateDockerCompose())
 	}
 	window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
 }
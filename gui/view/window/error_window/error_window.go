@@ -15,6 +15,7 @@ type ErrorWindow struct {
 	window_ctx    context.Context
 	window_cancel context.CancelFunc
 
+	title                string
 	message              []byte
 	dimensions_generator func() window.Dimensions
 	resize_ch            chan interface{}
@@ -24,7 +25,13 @@ type ErrorWindow struct {
 }
 
 func NewErrorWindow(message []byte) ErrorWindow {
+	return NewTextWindow("Error", message)
+}
+
+// NewTextWindow shows a scrollable message under the given title
+func NewTextWindow(title string, message []byte) ErrorWindow {
 	return ErrorWindow{
+		title:   title,
 		message: message,
 		dimensions_generator: func() window.Dimensions {
 			x1, y1, x2, y2 := window.ErrorWindowSize()
@@ -116,7 +123,7 @@ func (w *ErrorWindow) drawError() {
 	dimensions := w.dimensions_generator()
 	log.Print(string(clean_message))
 
-	header := elements.TextDrawer("Error", tcell.StyleDefault.Bold(true).Underline(true))
+	header := elements.TextDrawer(w.title, tcell.StyleDefault.Bold(true).Underline(true))
 	message_lines := make([][]byte, 0)
 	for prev_end, i := 0, 0; i < len(clean_message); i++ {
 		if clean_message[i] == '\n' || i-prev_end >= window.Width(&dimensions) {
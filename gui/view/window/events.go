@@ -1,6 +1,7 @@
 package window
 
 import (
+	"dc-top/docker"
 	"log"
 	"time"
 )
@@ -53,19 +54,20 @@ func NewResumeWindowsEvent() ResumeWindowsEvent {
 
 // ---------
 
+// ChangeToLogsWindowEvent shows the logs of the containers, merged when there are several
 type ChangeToLogsWindowEvent struct {
-	t           time.Time
-	ContainerId string
+	t            time.Time
+	ContainerIds []string
 }
 
 func (e ChangeToLogsWindowEvent) When() time.Time {
 	return e.t
 }
 
-func NewChangeToLogsWindowEvent(container_id string) ChangeToLogsWindowEvent {
+func NewChangeToLogsWindowEvent(container_ids ...string) ChangeToLogsWindowEvent {
 	return ChangeToLogsWindowEvent{
-		t:           time.Now(),
-		ContainerId: container_id,
+		t:            time.Now(),
+		ContainerIds: container_ids,
 	}
 }
 
@@ -122,16 +124,210 @@ func NewChangeToEdittorHelpEvent() ChangeToEdittorHelpEvent {
 type ChangeToContainerShellEvent struct {
 	t           time.Time
 	ContainerId string
+	Exec        docker.ExecSpec
 }
 
 func (e ChangeToContainerShellEvent) When() time.Time {
 	return e.t
 }
 
-func NewChangeToContainerShellEvent(container_id string) ChangeToContainerShellEvent {
+func NewChangeToContainerShellEvent(container_id string, exec docker.ExecSpec) ChangeToContainerShellEvent {
 	return ChangeToContainerShellEvent{
 		t:           time.Now(),
 		ContainerId: container_id,
+		Exec:        exec,
+	}
+}
+
+// ---------
+
+type ChangeToDebugShellEvent struct {
+	t           time.Time
+	ContainerId string
+}
+
+func (e ChangeToDebugShellEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToDebugShellEvent(container_id string) ChangeToDebugShellEvent {
+	return ChangeToDebugShellEvent{
+		t:           time.Now(),
+		ContainerId: container_id,
+	}
+}
+
+// ---------
+
+type ChangeToAttachEvent struct {
+	t           time.Time
+	ContainerId string
+}
+
+func (e ChangeToAttachEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToAttachEvent(container_id string) ChangeToAttachEvent {
+	return ChangeToAttachEvent{
+		t:           time.Now(),
+		ContainerId: container_id,
+	}
+}
+
+// ---------
+
+type ResumeSubshellsEvent struct {
+	t time.Time
+}
+
+func (e ResumeSubshellsEvent) When() time.Time {
+	return e.t
+}
+
+func NewResumeSubshellsEvent() ResumeSubshellsEvent {
+	return ResumeSubshellsEvent{
+		t: time.Now(),
+	}
+}
+
+// ---------
+
+type ChangeToCastPlayerEvent struct {
+	t    time.Time
+	Path string
+}
+
+func (e ChangeToCastPlayerEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToCastPlayerEvent(path string) ChangeToCastPlayerEvent {
+	return ChangeToCastPlayerEvent{
+		t:    time.Now(),
+		Path: path,
+	}
+}
+
+// ---------
+
+// CommandTarget is a container a command is run in
+type CommandTarget struct {
+	Id   string
+	Name string
+}
+
+type ChangeToCommandOutputEvent struct {
+	t       time.Time
+	Command string
+	Targets []CommandTarget
+}
+
+func (e ChangeToCommandOutputEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToCommandOutputEvent(command string, targets []CommandTarget) ChangeToCommandOutputEvent {
+	return ChangeToCommandOutputEvent{
+		t:       time.Now(),
+		Command: command,
+		Targets: targets,
+	}
+}
+
+// ---------
+
+type ChangeToFileBrowserEvent struct {
+	t           time.Time
+	ContainerId string
+}
+
+func (e ChangeToFileBrowserEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToFileBrowserEvent(container_id string) ChangeToFileBrowserEvent {
+	return ChangeToFileBrowserEvent{
+		t:           time.Now(),
+		ContainerId: container_id,
+	}
+}
+
+// ---------
+
+type ChangeToDiffEvent struct {
+	t           time.Time
+	ContainerId string
+}
+
+func (e ChangeToDiffEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToDiffEvent(container_id string) ChangeToDiffEvent {
+	return ChangeToDiffEvent{
+		t:           time.Now(),
+		ContainerId: container_id,
+	}
+}
+
+// ---------
+
+type ChangeToTopEvent struct {
+	t           time.Time
+	ContainerId string
+}
+
+func (e ChangeToTopEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToTopEvent(container_id string) ChangeToTopEvent {
+	return ChangeToTopEvent{
+		t:           time.Now(),
+		ContainerId: container_id,
+	}
+}
+
+// ---------
+
+type ChangeToPagerEvent struct {
+	t        time.Time
+	Title    string
+	Contents []byte
+	Note     string
+}
+
+func (e ChangeToPagerEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToPagerEvent(title string, contents []byte, note string) ChangeToPagerEvent {
+	return ChangeToPagerEvent{
+		t:        time.Now(),
+		Title:    title,
+		Contents: contents,
+		Note:     note,
+	}
+}
+
+// ---------
+
+type ChangeToContainerFileEdittorEvent struct {
+	t           time.Time
+	ContainerId string
+	Path        string
+}
+
+func (e ChangeToContainerFileEdittorEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToContainerFileEdittorEvent(container_id string, path string) ChangeToContainerFileEdittorEvent {
+	return ChangeToContainerFileEdittorEvent{
+		t:           time.Now(),
+		ContainerId: container_id,
+		Path:        path,
 	}
 }
 
@@ -173,6 +369,134 @@ func NewChangeToErrorEvent(message []byte) ChangeToErrorEvent {
 
 // ---------
 
+type ChangeToTextEvent struct {
+	t       time.Time
+	Title   string
+	Message []byte
+}
+
+func (e ChangeToTextEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToTextEvent(title string, message []byte) ChangeToTextEvent {
+	return ChangeToTextEvent{
+		t:       time.Now(),
+		Title:   title,
+		Message: message,
+	}
+}
+
+// ---------
+
+type ChangeToConfirmEvent struct {
+	t           time.Time
+	Prompt      string
+	ConfirmText string
+	Receiver    WindowType
+	Message     interface{}
+}
+
+func (e ChangeToConfirmEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToConfirmEvent(prompt string, confirm_text string, receiver WindowType, message interface{}) ChangeToConfirmEvent {
+	return ChangeToConfirmEvent{
+		t:           time.Now(),
+		Prompt:      prompt,
+		ConfirmText: confirm_text,
+		Receiver:    receiver,
+		Message:     message,
+	}
+}
+
+// ---------
+
+type ChangeToMenuEvent struct {
+	t        time.Time
+	Title    string
+	Items    []MenuItem
+	X        int
+	Y        int
+	Receiver WindowType
+}
+
+func (e ChangeToMenuEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToMenuEvent(title string, items []MenuItem, x, y int, receiver WindowType) ChangeToMenuEvent {
+	return ChangeToMenuEvent{
+		t:        time.Now(),
+		Title:    title,
+		Items:    items,
+		X:        x,
+		Y:        y,
+		Receiver: receiver,
+	}
+}
+
+// ---------
+
+type ChangeToPromptEvent struct {
+	t                time.Time
+	Title            string
+	InitialValue     string
+	History          []string
+	Receiver         WindowType
+	MessageGenerator func(value string) interface{}
+}
+
+func (e ChangeToPromptEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToPromptEvent(title string, initial_value string, receiver WindowType, message_generator func(value string) interface{}) ChangeToPromptEvent {
+	return ChangeToPromptEvent{
+		t:                time.Now(),
+		Title:            title,
+		InitialValue:     initial_value,
+		Receiver:         receiver,
+		MessageGenerator: message_generator,
+	}
+}
+
+// NewChangeToPromptWithHistoryEvent opens a prompt whose previous values can be recalled with Up and Down
+func NewChangeToPromptWithHistoryEvent(title string, history []string, receiver WindowType, message_generator func(value string) interface{}) ChangeToPromptEvent {
+	ev := NewChangeToPromptEvent(title, "", receiver, message_generator)
+	ev.History = history
+	return ev
+}
+
+// ---------
+
+type ChangeToFormEvent struct {
+	t        time.Time
+	Title    string
+	Labels   []string
+	Values   []string
+	Actions  []FormAction
+	Receiver WindowType
+}
+
+func (e ChangeToFormEvent) When() time.Time {
+	return e.t
+}
+
+func NewChangeToFormEvent(title string, labels []string, values []string, actions []FormAction, receiver WindowType) ChangeToFormEvent {
+	return ChangeToFormEvent{
+		t:        time.Now(),
+		Title:    title,
+		Labels:   labels,
+		Values:   values,
+		Actions:  actions,
+		Receiver: receiver,
+	}
+}
+
+// ---------
+
 type StopDrawingEvent struct {
 	t time.Time
 }
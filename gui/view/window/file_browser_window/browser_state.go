@@ -0,0 +1,131 @@
+package file_browser_window
+
+import (
+	"context"
+	"dc-top/docker"
+	"dc-top/utils"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type treeRow struct {
+	file  docker.ContainerFile
+	depth int
+}
+
+type browserState struct {
+	// the directory the tree is shown from
+	root string
+	// listings of the directories that were loaded, by path
+	tree     map[string][]docker.ContainerFile
+	expanded map[string]bool
+
+	rows        []treeRow
+	focused     int
+	top_row     int
+	status      string
+	loading     string
+	load_ctx    context.Context
+	load_cancel context.CancelFunc
+}
+
+func newBrowserState() *browserState {
+	return &browserState{
+		root:     "/",
+		tree:     make(map[string][]docker.ContainerFile),
+		expanded: make(map[string]bool),
+	}
+}
+
+func (state *browserState) isLoaded(dir string) bool {
+	_, ok := state.tree[dir]
+	return ok
+}
+
+// forget drops the cached listings under dir so they're loaded again
+func (state *browserState) forget(dir string) {
+	for listed_dir := range state.tree {
+		if listed_dir == dir || strings.HasPrefix(listed_dir, strings.TrimSuffix(dir, "/")+"/") {
+			delete(state.tree, listed_dir)
+		}
+	}
+	state.rebuildRows()
+}
+
+// rebuildRows flattens the expanded directories, keeping the focus on the same file
+func (state *browserState) rebuildRows() {
+	focused_path := ""
+	if file, ok := state.focusedFile(); ok {
+		focused_path = file.Path
+	}
+	state.rows = state.rows[:0]
+	state.appendRows(state.root, 0)
+	state.focused = 0
+	for i, row := range state.rows {
+		if row.file.Path == focused_path {
+			state.focused = i
+			break
+		}
+	}
+}
+
+func (state *browserState) appendRows(dir string, depth int) {
+	for _, file := range state.tree[dir] {
+		state.rows = append(state.rows, treeRow{file, depth})
+		if file.IsDir() && state.expanded[file.Path] {
+			state.appendRows(file.Path, depth+1)
+		}
+	}
+}
+
+func (state *browserState) focusedFile() (docker.ContainerFile, bool) {
+	if state.focused < 0 || state.focused >= len(state.rows) {
+		return docker.ContainerFile{}, false
+	}
+	return state.rows[state.focused].file, true
+}
+
+func (state *browserState) moveFocus(delta int) {
+	state.focused = utils.Min(utils.Max(state.focused+delta, 0), utils.Max(len(state.rows)-1, 0))
+}
+
+// collapseFocused collapses the focused directory, or moves the focus to the parent directory.
+// It returns false if the focused file is at the top of the tree
+func (state *browserState) collapseFocused() bool {
+	file, ok := state.focusedFile()
+	if !ok {
+		return false
+	}
+	if file.IsDir() && state.expanded[file.Path] {
+		state.expanded[file.Path] = false
+		state.rebuildRows()
+		return true
+	}
+	parent := path.Dir(file.Path)
+	for i := state.focused - 1; i >= 0; i-- {
+		if state.rows[i].file.Path == parent {
+			state.focused = i
+			return true
+		}
+	}
+	return false
+}
+
+func defaultDownloadDir() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	return dir
+}
+
+func expandHome(dir string) string {
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(dir, "~"))
+		}
+	}
+	return dir
+}
@@ -0,0 +1,116 @@
+package file_browser_window
+
+import (
+	"bytes"
+	"dc-top/gui/view/window"
+	"dc-top/utils"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/gdamore/tcell/v2"
+)
+
+var (
+	headerStyle  = tcell.StyleDefault.Bold(true)
+	dirStyle     = tcell.StyleDefault.Foreground(tcell.ColorBlue).Bold(true)
+	linkStyle    = tcell.StyleDefault.Foreground(tcell.ColorTeal)
+	fileStyle    = tcell.StyleDefault
+	focusedStyle = tcell.StyleDefault.Reverse(true)
+	statusStyle  = tcell.StyleDefault.Foreground(tcell.ColorYellow)
+)
+
+func (w *FileBrowserWindow) draw(state *browserState) {
+	dimensions := w.dimensions_generator()
+	height := window.Height(&dimensions)
+	page := utils.Max(height-2, 1)
+	if state.focused < state.top_row {
+		state.top_row = state.focused
+	} else if state.focused >= state.top_row+page {
+		state.top_row = state.focused - page + 1
+	}
+	state.top_row = utils.Max(utils.Min(state.top_row, len(state.rows)-page), 0)
+
+	header := []rune(fmt.Sprintf(" %s:%s", w.name, state.root))
+	status := []rune(" " + state.status)
+	if state.status == "" {
+		status = []rune(" Enter open, ←/→ collapse/expand, e edit, o open directory, d download, u upload, r reload, q close")
+	}
+	lines := make([][]rune, page)
+	for y := range lines {
+		if index := state.top_row + y; index < len(state.rows) {
+			lines[y] = state.formatRow(state.rows[index])
+		}
+	}
+	window.DrawContents(&dimensions, func(x, y int) (rune, tcell.Style) {
+		switch {
+		case y == 0:
+			if x < len(header) {
+				return header[x], headerStyle
+			}
+			return ' ', headerStyle
+		case y == height-1:
+			if x < len(status) {
+				return status[x], statusStyle
+			}
+			return ' ', tcell.StyleDefault
+		}
+		index := state.top_row + y - 1
+		if index >= len(state.rows) {
+			return ' ', tcell.StyleDefault
+		}
+		style := rowStyle(state.rows[index])
+		if index == state.focused {
+			style = focusedStyle
+		}
+		if line := lines[y-1]; x < len(line) {
+			return line[x], style
+		}
+		return ' ', style
+	})
+	window.GetScreen().Show()
+}
+
+func (state *browserState) formatRow(row treeRow) []rune {
+	file := row.file
+	size := ""
+	if file.IsDir() || file.Mode.IsRegular() {
+		size = formatSize(file.Size)
+	}
+	marker := "  "
+	if file.IsDir() {
+		marker = "▸ "
+		if state.expanded[file.Path] {
+			marker = "▾ "
+		}
+		if state.loading == file.Path {
+			marker = "… "
+		}
+	}
+	name := file.Name()
+	if file.Mode&os.ModeSymlink != 0 {
+		name += " -> " + file.LinkTarget
+	}
+	return []rune(fmt.Sprintf(" %-11s %9s  %s  %s%s%s",
+		file.Mode.String(), size, file.ModTime.Format("2006-01-02 15:04"), strings.Repeat("  ", row.depth), marker, name))
+}
+
+func rowStyle(row treeRow) tcell.Style {
+	switch {
+	case row.file.IsDir():
+		return dirStyle
+	case row.file.Mode&os.ModeSymlink != 0:
+		return linkStyle
+	}
+	return fileStyle
+}
+
+// isBinary guesses like git does, by looking for a NUL byte at the start of the file
+func isBinary(contents []byte) bool {
+	return bytes.IndexByte(contents[:utils.Min(len(contents), 8000)], 0) >= 0
+}
+
+func formatSize(size int64) string {
+	return units.BytesSize(float64(size))
+}
@@ -0,0 +1,490 @@
+package file_browser_window
+
+import (
+	"context"
+	"dc-top/config"
+	"dc-top/docker"
+	"dc-top/gui/view/window"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// files bigger than this are truncated in the pager
+const maxViewedFileSize = 10 * 1024 * 1024
+
+// files bigger than this aren't opened in the edittor, which reads them whole
+const maxEdittedFileSize = 1024 * 1024
+
+// DownloadRequest is sent by the download prompt
+type DownloadRequest struct {
+	ContainerPath string
+	HostDir       string
+}
+
+// UploadRequest is sent by the upload prompt
+type UploadRequest struct {
+	HostPath     string
+	ContainerDir string
+}
+
+// OpenPathRequest is sent by the path prompt, the tree is shown from that directory
+type OpenPathRequest struct {
+	Path string
+}
+
+// FileBrowserWindow browses a container's filesystem through the archive API, which works for stopped containers
+// and images without a shell
+type FileBrowserWindow struct {
+	window_ctx    context.Context
+	window_cancel context.CancelFunc
+
+	id   string
+	name string
+	// the browser opens at the container's working directory
+	working_dir string
+
+	dimensions_generator func() window.Dimensions
+	keyboard_ch          chan tcell.EventKey
+	resize_ch            chan interface{}
+	enable_toggle        chan bool
+	action_ch            chan interface{}
+}
+
+func NewFileBrowserWindow(id string) FileBrowserWindow {
+	return FileBrowserWindow{
+		id: id,
+		dimensions_generator: func() window.Dimensions {
+			w, h := window.GetScreen().Size()
+			return window.NewDimensions(0, 0, w-1, h-1, false)
+		},
+		keyboard_ch:   make(chan tcell.EventKey),
+		resize_ch:     make(chan interface{}),
+		enable_toggle: make(chan bool),
+		action_ch:     make(chan interface{}),
+	}
+}
+
+func (w *FileBrowserWindow) Open(view_ctx context.Context) {
+	w.window_ctx, w.window_cancel = context.WithCancel(view_ctx)
+	inspection := docker.InspectContainerNoPanic(w.window_ctx, w.id)
+	w.name = strings.TrimPrefix(inspection.Name, "/")
+	w.working_dir = "/"
+	if inspection.Config != nil && inspection.Config.WorkingDir != "" {
+		w.working_dir = inspection.Config.WorkingDir
+	}
+	go w.main()
+}
+
+func (w *FileBrowserWindow) Resize() {
+	select {
+	case w.resize_ch <- nil:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *FileBrowserWindow) KeyPress(ev tcell.EventKey) {
+	select {
+	case w.keyboard_ch <- ev:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *FileBrowserWindow) MousePress(_ tcell.EventMouse) {}
+
+func (w *FileBrowserWindow) HandleEvent(ev interface{}, sender window.WindowType) (interface{}, error) {
+	switch ev := ev.(type) {
+	case DownloadRequest, UploadRequest, OpenPathRequest:
+		select {
+		case w.action_ch <- ev:
+		case <-w.window_ctx.Done():
+		}
+	default:
+		log.Printf("Got unknown event in file browser %T", ev)
+	}
+	return nil, nil
+}
+
+func (w *FileBrowserWindow) Disable() {
+	log.Printf("Disable FileBrowserWindow...")
+	select {
+	case w.enable_toggle <- false:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *FileBrowserWindow) Enable() {
+	log.Printf("Enable FileBrowserWindow...")
+	select {
+	case w.enable_toggle <- true:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *FileBrowserWindow) Close() {
+	w.window_cancel()
+}
+
+type dirLoaded struct {
+	dir   string
+	files []docker.ContainerFile
+	err   error
+}
+
+type loadProgress struct {
+	dir     string
+	entries int
+}
+
+type fileRead struct {
+	file      docker.ContainerFile
+	contents  []byte
+	truncated bool
+	err       error
+}
+
+type downloadDone struct {
+	container_path string
+	host_path      string
+	err            error
+}
+
+type uploadDone struct {
+	host_path     string
+	container_dir string
+	err           error
+}
+
+func (w *FileBrowserWindow) main() {
+	window.GetScreen().Clear()
+	state := newBrowserState()
+	results_ch := make(chan interface{})
+	is_enabled := true
+	w.openRoot(state, w.working_dir, results_ch)
+	for {
+		if is_enabled {
+			w.draw(state)
+		}
+		select {
+		case ev := <-w.keyboard_ch:
+			if w.handleKeyPress(state, &ev, results_ch) {
+				return
+			}
+		case result := <-results_ch:
+			w.handleResult(state, result)
+		case action := <-w.action_ch:
+			switch action := action.(type) {
+			case DownloadRequest:
+				w.download(state, action, results_ch)
+			case UploadRequest:
+				w.upload(state, action, results_ch)
+			case OpenPathRequest:
+				w.openRoot(state, action.Path, results_ch)
+			}
+		case <-w.resize_ch:
+			window.GetScreen().Clear()
+		case is_enabled = <-w.enable_toggle:
+		case <-w.window_ctx.Done():
+			return
+		}
+	}
+}
+
+// load lists dir in the background, the listing is cached until it's reloaded
+func (w *FileBrowserWindow) load(state *browserState, dir string, results_ch chan<- interface{}) {
+	if state.loading != "" {
+		return
+	}
+	state.loading = dir
+	state.load_ctx, state.load_cancel = context.WithCancel(w.window_ctx)
+	load_ctx := state.load_ctx
+	go func() {
+		files, err := docker.ListDir(load_ctx, w.id, dir, func(entries int) {
+			select {
+			case results_ch <- loadProgress{dir, entries}:
+			case <-load_ctx.Done():
+			}
+		})
+		select {
+		case results_ch <- dirLoaded{dir, files, err}:
+		case <-w.window_ctx.Done():
+		}
+	}()
+}
+
+func (w *FileBrowserWindow) handleResult(state *browserState, result interface{}) {
+	switch result := result.(type) {
+	case loadProgress:
+		if result.dir == state.loading {
+			state.status = fmt.Sprintf("Listing %s... %d entries", result.dir, result.entries)
+		}
+	case dirLoaded:
+		if result.dir != state.loading {
+			return
+		}
+		state.loading = ""
+		state.load_cancel()
+		if result.err != nil {
+			state.status = fmt.Sprintf("Failed to list %s: %s", result.dir, result.err)
+			return
+		}
+		state.tree[result.dir] = result.files
+		state.expanded[result.dir] = true
+		state.status = ""
+		state.rebuildRows()
+	case fileRead:
+		if result.err != nil {
+			state.status = fmt.Sprintf("Failed to read %s: %s", result.file.Path, result.err)
+			return
+		}
+		if isBinary(result.contents) {
+			state.status = fmt.Sprintf("%s is a binary file, press 'd' to download it", result.file.Path)
+			return
+		}
+		note := ""
+		if result.truncated {
+			note = fmt.Sprintf("[only the first %s are shown]", formatSize(maxViewedFileSize))
+		}
+		state.status = ""
+		window.GetScreen().PostEvent(window.NewChangeToPagerEvent(fmt.Sprintf("%s:%s", w.name, result.file.Path), result.contents, note))
+	case downloadDone:
+		if result.err != nil {
+			state.status = fmt.Sprintf("Failed to download %s: %s", result.container_path, result.err)
+		} else {
+			state.status = fmt.Sprintf("Downloaded %s to %s", result.container_path, result.host_path)
+		}
+	case uploadDone:
+		if result.err != nil {
+			state.status = fmt.Sprintf("Failed to upload %s: %s", result.host_path, result.err)
+		} else {
+			state.status = fmt.Sprintf("Uploaded %s to %s, press 'r' there to list it again", result.host_path, result.container_dir)
+		}
+	}
+}
+
+// handleKeyPress returns true when the browser is closed
+func (w *FileBrowserWindow) handleKeyPress(state *browserState, ev *tcell.EventKey, results_ch chan<- interface{}) bool {
+	page := w.pageHeight()
+	switch ev.Key() {
+	case tcell.KeyUp:
+		state.moveFocus(-1)
+	case tcell.KeyDown:
+		state.moveFocus(1)
+	case tcell.KeyPgUp:
+		state.moveFocus(-page)
+	case tcell.KeyPgDn:
+		state.moveFocus(page)
+	case tcell.KeyHome:
+		state.moveFocus(-len(state.rows))
+	case tcell.KeyEnd:
+		state.moveFocus(len(state.rows))
+	case tcell.KeyEnter, tcell.KeyRight:
+		w.openFocused(state, ev.Key() == tcell.KeyEnter, results_ch)
+	case tcell.KeyLeft, tcell.KeyBackspace, tcell.KeyBackspace2:
+		if !state.collapseFocused() && state.root != "/" {
+			w.openRoot(state, path.Dir(state.root), results_ch)
+		}
+	case tcell.KeyEscape:
+		if state.loading != "" {
+			state.load_cancel()
+			state.loading = ""
+			state.status = "Listing cancelled"
+			return false
+		}
+		w.quit()
+		return true
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'q':
+			w.quit()
+			return true
+		case 'g':
+			state.moveFocus(-len(state.rows))
+		case 'G':
+			state.moveFocus(len(state.rows))
+		case 'r':
+			dir := state.root
+			if file, ok := state.focusedFile(); ok {
+				dir = path.Dir(file.Path)
+				if file.IsDir() {
+					dir = file.Path
+				}
+			}
+			state.forget(dir)
+			w.load(state, dir, results_ch)
+		case 'd':
+			if file, ok := state.focusedFile(); ok {
+				w.requestDownload(file)
+			}
+		case 'e':
+			if file, ok := state.focusedFile(); ok {
+				w.edit(state, file)
+			}
+		case 'u':
+			w.requestUpload(state)
+		case 'o':
+			window.GetScreen().PostEvent(window.NewChangeToPromptEvent("Open directory", state.root, window.FileBrowser,
+				func(dir string) interface{} {
+					return OpenPathRequest{Path: dir}
+				}))
+		}
+	}
+	return false
+}
+
+func (w *FileBrowserWindow) openFocused(state *browserState, open_files bool, results_ch chan<- interface{}) {
+	file, ok := state.focusedFile()
+	if !ok {
+		return
+	}
+	if file.IsDir() {
+		if state.expanded[file.Path] && open_files {
+			state.expanded[file.Path] = false
+			state.rebuildRows()
+		} else if state.isLoaded(file.Path) {
+			state.expanded[file.Path] = true
+			state.rebuildRows()
+		} else {
+			w.load(state, file.Path, results_ch)
+		}
+		return
+	}
+	if !open_files {
+		return
+	}
+	file_path, ok := contentPath(file)
+	if !ok {
+		state.status = fmt.Sprintf("%s isn't a regular file", file.Path)
+		return
+	}
+	state.status = fmt.Sprintf("Reading %s...", file.Path)
+	go func() {
+		contents, truncated, err := docker.ReadContainerFile(w.window_ctx, w.id, file_path, maxViewedFileSize)
+		select {
+		case results_ch <- fileRead{file, contents, truncated, err}:
+		case <-w.window_ctx.Done():
+		}
+	}()
+}
+
+// contentPath is the path to read the contents of a file from, the archive API copies symlinks themselves
+// so their target is read instead
+func contentPath(file docker.ContainerFile) (string, bool) {
+	if file.Mode&os.ModeSymlink != 0 {
+		if path.IsAbs(file.LinkTarget) {
+			return file.LinkTarget, true
+		}
+		return path.Join(path.Dir(file.Path), file.LinkTarget), true
+	}
+	return file.Path, file.Mode.IsRegular()
+}
+
+func (w *FileBrowserWindow) edit(state *browserState, file docker.ContainerFile) {
+	file_path, ok := contentPath(file)
+	switch {
+	case config.IsReadOnly():
+		state.status = config.ErrReadOnly.Error()
+	case !ok:
+		state.status = fmt.Sprintf("%s isn't a regular file", file.Path)
+	case file.Size > maxEdittedFileSize:
+		state.status = fmt.Sprintf("%s is too big to edit", file.Path)
+	default:
+		state.status = ""
+		window.GetScreen().PostEvent(window.NewChangeToContainerFileEdittorEvent(w.id, file_path))
+	}
+}
+
+// requestUpload asks for a host path to copy into the focused directory, or the directory of the focused file
+func (w *FileBrowserWindow) requestUpload(state *browserState) {
+	if config.IsReadOnly() {
+		state.status = config.ErrReadOnly.Error()
+		return
+	}
+	container_dir := state.root
+	if file, ok := state.focusedFile(); ok {
+		container_dir = path.Dir(file.Path)
+		if file.IsDir() {
+			container_dir = file.Path
+		}
+	}
+	window.GetScreen().PostEvent(window.NewChangeToPromptEvent(
+		fmt.Sprintf("Upload to %s from host path", container_dir),
+		defaultDownloadDir(),
+		window.FileBrowser,
+		func(host_path string) interface{} {
+			return UploadRequest{HostPath: host_path, ContainerDir: container_dir}
+		}))
+}
+
+func (w *FileBrowserWindow) upload(state *browserState, request UploadRequest, results_ch chan<- interface{}) {
+	if strings.TrimSpace(request.HostPath) == "" {
+		return
+	}
+	state.status = fmt.Sprintf("Uploading %s...", request.HostPath)
+	go func() {
+		err := docker.UploadToContainer(w.window_ctx, w.id, expandHome(request.HostPath), request.ContainerDir)
+		select {
+		case results_ch <- uploadDone{request.HostPath, request.ContainerDir, err}:
+		case <-w.window_ctx.Done():
+		}
+	}()
+}
+
+// openRoot shows the tree from dir, listing a deep directory is much faster than listing everything from "/", so "/"
+// is only listed with 'r'
+func (w *FileBrowserWindow) openRoot(state *browserState, dir string, results_ch chan<- interface{}) {
+	dir = path.Clean("/" + strings.TrimSpace(dir))
+	if state.loading != "" {
+		state.load_cancel()
+		state.loading = ""
+	}
+	state.root = dir
+	state.focused = 0
+	state.rebuildRows()
+	if state.isLoaded(dir) {
+		return
+	}
+	if dir == "/" {
+		state.status = "Listing / reads the whole filesystem, press 'r' to list it or 'o' to open a directory"
+		return
+	}
+	w.load(state, dir, results_ch)
+}
+
+func (w *FileBrowserWindow) requestDownload(file docker.ContainerFile) {
+	window.GetScreen().PostEvent(window.NewChangeToPromptEvent(
+		fmt.Sprintf("Download %s to directory", file.Path),
+		defaultDownloadDir(),
+		window.FileBrowser,
+		func(host_dir string) interface{} {
+			return DownloadRequest{ContainerPath: file.Path, HostDir: host_dir}
+		}))
+}
+
+func (w *FileBrowserWindow) download(state *browserState, request DownloadRequest, results_ch chan<- interface{}) {
+	if strings.TrimSpace(request.HostDir) == "" {
+		return
+	}
+	state.status = fmt.Sprintf("Downloading %s...", request.ContainerPath)
+	go func() {
+		host_path, err := docker.DownloadFromContainer(w.window_ctx, w.id, request.ContainerPath, expandHome(request.HostDir))
+		select {
+		case results_ch <- downloadDone{request.ContainerPath, host_path, err}:
+		case <-w.window_ctx.Done():
+		}
+	}()
+}
+
+func (w *FileBrowserWindow) quit() {
+	w.window_cancel()
+	window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+}
+
+func (w *FileBrowserWindow) pageHeight() int {
+	dimensions := w.dimensions_generator()
+	return window.Height(&dimensions) - 2
+}
@@ -0,0 +1,147 @@
+package form_window
+
+import (
+	"context"
+	"dc-top/gui/elements"
+	"dc-top/gui/view/window"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type FormWindow struct {
+	window_ctx    context.Context
+	window_cancel context.CancelFunc
+
+	title    string
+	actions  []window.FormAction
+	receiver window.WindowType
+
+	dimensions_generator func() window.Dimensions
+	form                 elements.Form
+	is_enabled           bool
+}
+
+func NewFormWindow(title string, labels []string, values []string, actions []window.FormAction, receiver window.WindowType) FormWindow {
+	return FormWindow{
+		title:    title,
+		actions:  actions,
+		receiver: receiver,
+		dimensions_generator: func() window.Dimensions {
+			x1, y1, x2, y2 := window.FormWindowSize(len(labels) + 4)
+			return window.NewDimensions(x1, y1, x2, y2, true)
+		},
+		form: elements.NewForm(labels, values,
+			tcell.StyleDefault.Underline(true),
+			tcell.StyleDefault.Background(tcell.ColorWhite).Foreground(tcell.ColorBlack)),
+		is_enabled: true,
+	}
+}
+
+func (w *FormWindow) Open(view_ctx context.Context) {
+	log.Println("Opening form")
+	w.window_ctx, w.window_cancel = context.WithCancel(view_ctx)
+	w.drawForm()
+}
+
+func (w *FormWindow) Resize() {
+	w.drawForm()
+}
+
+func (w *FormWindow) KeyPress(ev tcell.EventKey) {
+	for _, action := range w.actions {
+		if ev.Key() == action.Key {
+			w.runAction(action)
+			return
+		}
+	}
+	switch ev.Key() {
+	case tcell.KeyEscape, tcell.KeyCtrlD:
+		window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+		return
+	default:
+		w.form.HandleKey(&ev)
+	}
+	w.drawForm()
+}
+
+func (w *FormWindow) MousePress(_ tcell.EventMouse) {}
+
+func (w *FormWindow) HandleEvent(ev interface{}, _ window.WindowType) (interface{}, error) {
+	switch ev := ev.(type) {
+	case SetFieldValue:
+		w.form.SetValue(ev.Field, ev.Value)
+		w.drawForm()
+	default:
+		window.ExitIfErr(errors.New("shouldn't have gotten here"))
+	}
+	return nil, nil
+}
+
+func (w *FormWindow) Disable() {
+	log.Printf("Disable FormWindow...")
+	w.is_enabled = false
+}
+
+func (w *FormWindow) Enable() {
+	log.Printf("Enable FormWindow...")
+	w.is_enabled = true
+	w.drawForm()
+}
+
+func (w *FormWindow) Close() {
+	w.window_cancel()
+}
+
+func (w *FormWindow) runAction(action window.FormAction) {
+	screen := window.GetScreen()
+	if !action.KeepOpen {
+		screen.PostEvent(window.NewReturnUpperViewEvent())
+	}
+	// actions that open another popup on top of the form do it themselves and have nothing to send
+	if message := action.MessageGenerator(w.form.Values()); message != nil {
+		screen.PostEvent(window.NewMessageEvent(w.receiver, window.Form, message))
+	}
+}
+
+func (w *FormWindow) drawForm() {
+	if !w.is_enabled {
+		return
+	}
+	dimensions := w.dimensions_generator()
+	action_descriptions := make([]string, 0, len(w.actions)+1)
+	for _, action := range w.actions {
+		action_descriptions = append(action_descriptions, action.KeyName+": "+action.Label)
+	}
+	action_descriptions = append(action_descriptions, "Esc: cancel")
+
+	lines := []elements.StringStyler{
+		elements.TextDrawer(w.title, tcell.StyleDefault.Bold(true).Underline(true)),
+		elements.EmptyDrawer(),
+	}
+	lines = append(lines, w.form.Style()...)
+	lines = append(lines,
+		elements.EmptyDrawer(),
+		elements.TextDrawer(strings.Join(action_descriptions, ", "), tcell.StyleDefault.Foreground(tcell.ColorGray)),
+	)
+	var drawer = func(x, y int) (rune, tcell.Style) {
+		if y < len(lines) {
+			r, s := lines[y](x)
+			if r == '\x00' {
+				r = ' '
+			}
+			return r, s
+		}
+		return ' ', tcell.StyleDefault
+	}
+	window.DrawContents(&dimensions, drawer)
+	window.GetScreen().Show()
+}
+
+// SetFieldValue can be sent to an open form in order to change one of its fields
+type SetFieldValue struct {
+	Field int
+	Value string
+}
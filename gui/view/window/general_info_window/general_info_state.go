@@ -1,6 +1,7 @@
 package general_info_window
 
 import (
+	"dc-top/config"
 	"dc-top/docker/compose"
 	"dc-top/gui/elements"
 
@@ -25,9 +26,14 @@ func generalInfoDrawerGenerator(state *generalInfoState, width int) func(x, y in
 }
 
 func getDcModeStatus() string {
+	var status string
 	if compose.DcModeEnabled() {
-		return "Docker Compose mode is enabled."
+		status = "Docker Compose mode is enabled."
 	} else {
-		return "Docker Compose mode is disabled, showing all dockerd containers."
+		status = "Docker Compose mode is disabled, showing all dockerd containers."
 	}
+	if config.IsReadOnly() {
+		status += " Read-only mode."
+	}
+	return status
 }
@@ -30,6 +30,47 @@ func ErrorWindowSize() (x1, y1, x2, y2 int) {
 	return width / 4, height / 4, 3 * width / 4, 3 * height / 4
 }
 
+func ConfirmWindowSize() (x1, y1, x2, y2 int) {
+	width, height := GetScreen().Size()
+	return width / 4, height / 3, 3 * width / 4, 2 * height / 3
+}
+
+func PromptWindowSize() (x1, y1, x2, y2 int) {
+	width, height := GetScreen().Size()
+	return width / 4, height/2 - 2, 3 * width / 4, height/2 + 1
+}
+
+func FormWindowSize(form_height int) (x1, y1, x2, y2 int) {
+	width, height := GetScreen().Size()
+	top := (height - form_height) / 2
+	if top < 0 {
+		top = 0
+	}
+	return width / 6, top, 5 * width / 6, top + form_height + 1
+}
+
+// MenuWindowSize places a menu of the given size at (x, y), moving it back inside the screen if needed.
+// Menus taller than the screen are cut and have to scroll
+func MenuWindowSize(x, y, menu_width, menu_height int) (x1, y1, x2, y2 int) {
+	width, height := GetScreen().Size()
+	if menu_height > height {
+		menu_height = height
+	}
+	if x+menu_width > width {
+		x = width - menu_width
+	}
+	if y+menu_height > height {
+		y = height - menu_height
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return x, y, x + menu_width - 1, y + menu_height - 1
+}
+
 func LogsWindowSize() (x1, y1, x2, y2 int) {
 	width, height := GetScreen().Size()
 	return 0, 0, width - 1, height - 2
@@ -8,17 +8,36 @@ type Control struct {
 func MainControls() []Control {
 	return []Control{
 		{"'h'", "Display more controls"},
-		{"'l'", "Watch container logs"},
+		{"'l'", "Watch container logs, the logs of the selected containers are merged"},
+		{"'M'", "Watch the merged logs of all the shown containers"},
 		{"'e'", "Open shell inside selected container"},
+		{"'E'", "Exec a command inside selected container, choosing user, workdir and env"},
+		{"'x'", "Run a command in selected containers (or focused one) and show its output"},
+		{"'A'", "Attach to the main process of selected container (Ctrl+P,Ctrl+Q to detach)"},
+		{"'T'", "Return to the shell sessions running in the background"},
+		{"'P'", "Replay a recorded shell session"},
+		{"'F'", "Browse the files of selected container, works for stopped containers too"},
+		{"'p'", "List the processes of selected container, sort them and send signals to single processes"},
+		{"'D'", "List the paths selected container added, changed or deleted relative to its image"},
+		{"'d'", "Debug selected container from a sidecar sharing its PID and network namespaces"},
 		{"'/'", "Filter containers"},
 		{"'c'", "Clear filter"},
 		{"'v'", "Edit docker-compose yaml"},
 		{"'i'", "Inspect selected container"},
 		{"'f'", "Toggle docker-compose filtering"},
-		{"Ctrl+P", "Pause selected container"},
-		{"Ctrl+R", "Restart selected container"},
-		{"Delete", "Remove selected container"},
-		{"Ctrl+S", "Stop selected container"},
+		{"Space", "Toggle selection of focused container"},
+		{"'a'", "Select all filtered containers"},
+		{"'s'", "Select containers by query"},
+		{"'u'", "Clear selection"},
+		{"Ctrl+P", "Pause selected container(s)"},
+		{"Ctrl+R", "Restart selected container(s)"},
+		{"Delete", "Remove selected container(s)"},
+		{"Ctrl+S", "Stop selected container(s)"},
+		{"'S'", "Gracefully stop selected container(s), offering SIGKILL on timeout"},
+		{"'K'", "Kill selected container(s)"},
+		{"'L'", "Edit resource limits of focused container"},
+		{"'m'/Right click", "Open actions menu of focused container"},
+		{"'r'", "Run a new container"},
 		{"Ctrl+U", "Update docker compose"},
 		{"Ctrl+W", "Restart docker compose"},
 		{"Ctrl+D", "Remove (down) docker compose"},
@@ -35,11 +54,23 @@ func LogControls() []Control {
 		{"'h'", "Display controls"},
 		{"'l'/'q'", "Exit current logs"},
 		{"'/'", "Search inside logs"},
+		{"Alt+r/c/w", "While searching, toggle regex, case sensitive and whole word search"},
 		{"'c'", "Clear search"},
 		{"'n'", "Jump to next search result"},
 		{"'N'", "Jump to previous search result"},
+		{"'&'", "Show only lines matching a filter, '-v PATTERN' hides matching lines, 'level>=warn' compares fields"},
+		{"'u'/'U'", "Remove the last filter/all filters"},
+		{"'J'", "Show JSON and logfmt lines as they are/by their fields"},
+		{"'d'", "Show every field of the last shown line"},
+		{"'o'/'e'", "Show only stdout/stderr"},
+		{"'s'/'1'-'9'", "Show/hide the logs of a container in merged logs"},
 		{"Up/Down", "Browse logs"},
+		{"PgUp/PgDn", "Browse logs a page at a time"},
+		{"Home", "Go to the oldest kept log"},
 		{"'f'", "Resume following logs"},
+		{"'t'", "Show/hide timestamps"},
+		{"'R'", "Load the logs of a time range (e.g. since 15m, until 13:00)"},
+		{"'j'", "Jump to the first log written at or after a time"},
 	}
 }
 
@@ -0,0 +1,184 @@
+package menu_window
+
+import (
+	"context"
+	"dc-top/gui/elements"
+	"dc-top/gui/view/window"
+	"dc-top/utils"
+	"errors"
+	"log"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type MenuWindow struct {
+	window_ctx    context.Context
+	window_cancel context.CancelFunc
+
+	title    string
+	items    []window.MenuItem
+	receiver window.WindowType
+
+	dimensions_generator func() window.Dimensions
+	focused_item         int
+	top_item             int
+	is_enabled           bool
+}
+
+// NewMenuWindow creates a popup menu whose top left corner is as close as possible to (x, y)
+func NewMenuWindow(title string, items []window.MenuItem, x, y int, receiver window.WindowType) MenuWindow {
+	return MenuWindow{
+		title:    title,
+		items:    items,
+		receiver: receiver,
+		dimensions_generator: func() window.Dimensions {
+			x1, y1, x2, y2 := window.MenuWindowSize(x, y, menuWidth(title, items), len(items)+3)
+			return window.NewDimensions(x1, y1, x2, y2, true)
+		},
+		focused_item: 0,
+		is_enabled:   true,
+	}
+}
+
+func (w *MenuWindow) Open(view_ctx context.Context) {
+	log.Println("Opening menu")
+	w.window_ctx, w.window_cancel = context.WithCancel(view_ctx)
+	w.drawMenu()
+}
+
+func (w *MenuWindow) Resize() {
+	w.drawMenu()
+}
+
+func (w *MenuWindow) KeyPress(ev tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyUp:
+		w.focused_item = (w.focused_item - 1 + len(w.items)) % len(w.items)
+	case tcell.KeyDown:
+		w.focused_item = (w.focused_item + 1) % len(w.items)
+	case tcell.KeyPgUp:
+		w.focused_item = utils.Max(w.focused_item-w.visibleItems(), 0)
+	case tcell.KeyPgDn:
+		w.focused_item = utils.Min(w.focused_item+w.visibleItems(), len(w.items)-1)
+	case tcell.KeyHome:
+		w.focused_item = 0
+	case tcell.KeyEnd:
+		w.focused_item = len(w.items) - 1
+	case tcell.KeyEnter:
+		w.choose(w.focused_item)
+		return
+	case tcell.KeyEscape, tcell.KeyCtrlD:
+		w.cancel()
+		return
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'q', 'm':
+			w.cancel()
+			return
+		case ' ':
+			w.choose(w.focused_item)
+			return
+		}
+	}
+	w.drawMenu()
+}
+
+func (w *MenuWindow) MousePress(ev tcell.EventMouse) {
+	if ev.Buttons()&tcell.Button1 == 0 {
+		return
+	}
+	dimensions := w.dimensions_generator()
+	if dimensions.IsOutbounds(&ev) {
+		w.cancel()
+		return
+	}
+	_, y := dimensions.RelativeMousePosition(&ev)
+	item_index := y - 2 + w.top_item // border and title
+	if item_index >= 0 && item_index < len(w.items) {
+		w.choose(item_index)
+	}
+}
+
+func (w *MenuWindow) HandleEvent(interface{}, window.WindowType) (interface{}, error) {
+	window.ExitIfErr(errors.New("shouldn't have gotten here"))
+	panic(1)
+}
+
+func (w *MenuWindow) Disable() {
+	log.Printf("Disable MenuWindow...")
+	w.is_enabled = false
+}
+
+func (w *MenuWindow) Enable() {
+	log.Printf("Enable MenuWindow...")
+	w.is_enabled = true
+	w.drawMenu()
+}
+
+func (w *MenuWindow) Close() {
+	w.window_cancel()
+}
+
+func (w *MenuWindow) choose(index int) {
+	screen := window.GetScreen()
+	screen.PostEvent(window.NewReturnUpperViewEvent())
+	screen.PostEvent(window.NewMessageEvent(w.receiver, window.Menu, w.items[index].Message))
+}
+
+func (w *MenuWindow) cancel() {
+	window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+}
+
+func (w *MenuWindow) drawMenu() {
+	if !w.is_enabled {
+		return
+	}
+	dimensions := w.dimensions_generator()
+	visible_items := utils.Max(window.Height(&dimensions)-1, 1)
+	if w.focused_item < w.top_item {
+		w.top_item = w.focused_item
+	} else if w.focused_item >= w.top_item+visible_items {
+		w.top_item = w.focused_item - visible_items + 1
+	}
+	lines := []elements.StringStyler{
+		elements.TextDrawer(w.title, tcell.StyleDefault.Bold(true).Underline(true)),
+	}
+	for i := w.top_item; i < len(w.items) && i < w.top_item+visible_items; i++ {
+		item := w.items[i]
+		style := tcell.StyleDefault
+		if i == w.focused_item {
+			style = style.Background(tcell.ColorDarkBlue)
+		}
+		item_styler := elements.TextDrawer(" "+item.Label, style)
+		lines = append(lines, func(x int) (rune, tcell.Style) {
+			r, _ := item_styler(x)
+			if r == '\x00' {
+				r = ' '
+			}
+			return r, style
+		})
+	}
+	var drawer = func(x, y int) (rune, tcell.Style) {
+		if y < len(lines) {
+			return lines[y](x)
+		}
+		return ' ', tcell.StyleDefault
+	}
+	window.DrawContents(&dimensions, drawer)
+	window.GetScreen().Show()
+}
+
+func (w *MenuWindow) visibleItems() int {
+	dimensions := w.dimensions_generator()
+	return utils.Max(window.Height(&dimensions)-1, 1)
+}
+
+func menuWidth(title string, items []window.MenuItem) int {
+	width := len(title)
+	for _, item := range items {
+		if len(item.Label)+1 > width {
+			width = len(item.Label) + 1
+		}
+	}
+	return width + 3
+}
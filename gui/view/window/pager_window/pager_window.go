@@ -0,0 +1,265 @@
+package pager_window
+
+import (
+	"bytes"
+	"context"
+	"dc-top/gui/elements"
+	"dc-top/gui/view/window"
+	"dc-top/utils"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/acarl005/stripansi"
+	"github.com/gdamore/tcell/v2"
+)
+
+// PagerWindow shows a text read-only, with search
+type PagerWindow struct {
+	window_ctx    context.Context
+	window_cancel context.CancelFunc
+
+	title string
+	// note is shown in the status line, e.g. when the text was truncated
+	note  string
+	lines [][]rune
+
+	dimensions_generator func() window.Dimensions
+	is_enabled           bool
+	top_line             int
+	left_column          int
+
+	search_box  elements.TextBox
+	is_typing   bool
+	search_term []rune
+	match_line  int
+}
+
+func NewPagerWindow(title string, contents []byte, note string) PagerWindow {
+	text := strings.ReplaceAll(stripansi.Strip(string(bytes.ToValidUTF8(contents, []byte("�")))), "\t", "    ")
+	text = strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	raw_lines := strings.Split(text, "\n")
+	lines := make([][]rune, len(raw_lines))
+	for i, line := range raw_lines {
+		lines[i] = []rune(line)
+	}
+	return PagerWindow{
+		title: title,
+		note:  note,
+		lines: lines,
+		dimensions_generator: func() window.Dimensions {
+			w, h := window.GetScreen().Size()
+			return window.NewDimensions(0, 0, w-1, h-1, false)
+		},
+		is_enabled: true,
+		search_box: elements.NewTextBox(
+			elements.TextDrawer("/", tcell.StyleDefault.Foreground(tcell.ColorYellow)),
+			1,
+			tcell.StyleDefault,
+			tcell.StyleDefault.Background(tcell.ColorWhite).Foreground(tcell.ColorBlack),
+			true),
+		match_line: -1,
+	}
+}
+
+func (w *PagerWindow) Open(view_ctx context.Context) {
+	log.Println("Opening pager")
+	w.window_ctx, w.window_cancel = context.WithCancel(view_ctx)
+	window.GetScreen().Clear()
+	w.draw()
+}
+
+func (w *PagerWindow) Resize() {
+	window.GetScreen().Clear()
+	w.draw()
+}
+
+func (w *PagerWindow) KeyPress(ev tcell.EventKey) {
+	if w.is_typing {
+		w.handleSearchKey(&ev)
+	} else {
+		w.handleKey(&ev)
+	}
+	w.draw()
+}
+
+func (w *PagerWindow) MousePress(_ tcell.EventMouse) {}
+
+func (w *PagerWindow) HandleEvent(interface{}, window.WindowType) (interface{}, error) {
+	panic(1)
+}
+
+func (w *PagerWindow) Disable() {
+	log.Printf("Disable PagerWindow...")
+	w.is_enabled = false
+}
+
+func (w *PagerWindow) Enable() {
+	log.Printf("Enable PagerWindow...")
+	w.is_enabled = true
+	w.draw()
+}
+
+func (w *PagerWindow) Close() {
+	w.window_cancel()
+}
+
+func (w *PagerWindow) pageHeight() int {
+	dimensions := w.dimensions_generator()
+	return window.Height(&dimensions) - 1
+}
+
+func (w *PagerWindow) handleKey(ev *tcell.EventKey) {
+	page := w.pageHeight()
+	switch ev.Key() {
+	case tcell.KeyUp:
+		w.top_line--
+	case tcell.KeyDown, tcell.KeyEnter:
+		w.top_line++
+	case tcell.KeyPgUp:
+		w.top_line -= page
+	case tcell.KeyPgDn:
+		w.top_line += page
+	case tcell.KeyHome:
+		w.top_line = 0
+	case tcell.KeyEnd:
+		w.top_line = len(w.lines)
+	case tcell.KeyLeft:
+		w.left_column = utils.Max(w.left_column-8, 0)
+	case tcell.KeyRight:
+		w.left_column += 8
+	case tcell.KeyEscape:
+		window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'q':
+			window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+		case 'j':
+			w.top_line++
+		case 'k':
+			w.top_line--
+		case ' ':
+			w.top_line += page
+		case 'b':
+			w.top_line -= page
+		case 'g':
+			w.top_line = 0
+		case 'G':
+			w.top_line = len(w.lines)
+		case '/':
+			w.search_box.Reset()
+			w.is_typing = true
+		case 'n':
+			w.findMatch(w.match_line+1, 1)
+		case 'N':
+			w.findMatch(w.match_line-1, -1)
+		}
+	}
+	w.top_line = utils.Min(utils.Max(w.top_line, 0), utils.Max(len(w.lines)-page, 0))
+}
+
+func (w *PagerWindow) handleSearchKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEnter:
+		w.is_typing = false
+		w.search_term = []rune(strings.ToLower(w.search_box.Value()))
+		w.findMatch(w.top_line, 1)
+	case tcell.KeyEscape:
+		w.is_typing = false
+	default:
+		w.search_box.HandleKey(ev)
+	}
+}
+
+// findMatch looks for the search term from line `from`, in the given direction, wrapping around the text
+func (w *PagerWindow) findMatch(from int, direction int) {
+	if len(w.search_term) == 0 || len(w.lines) == 0 {
+		return
+	}
+	for i := 0; i < len(w.lines); i++ {
+		line := ((from+i*direction)%len(w.lines) + len(w.lines)) % len(w.lines)
+		if matchColumn(w.lines[line], w.search_term) >= 0 {
+			w.match_line = line
+			w.top_line = utils.Max(line-w.pageHeight()/2, 0)
+			return
+		}
+	}
+	w.match_line = -1
+}
+
+func matchColumn(line []rune, term []rune) int {
+	lower := []rune(strings.ToLower(string(line)))
+	for i := 0; i+len(term) <= len(lower); i++ {
+		if string(lower[i:i+len(term)]) == string(term) {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchMask marks the columns of the case insensitive matches of term
+func matchMask(line []rune, term []rune) []bool {
+	mask := make([]bool, len(line))
+	lower := []rune(strings.ToLower(string(line)))
+	if len(lower) != len(line) {
+		return mask
+	}
+	for i := 0; i+len(term) <= len(lower); i++ {
+		if string(lower[i:i+len(term)]) == string(term) {
+			for j := i; j < i+len(term); j++ {
+				mask[j] = true
+			}
+		}
+	}
+	return mask
+}
+
+func (w *PagerWindow) draw() {
+	if !w.is_enabled {
+		return
+	}
+	dimensions := w.dimensions_generator()
+	height := window.Height(&dimensions)
+	match_style := tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorBlack)
+	status := w.statusLine()
+	// columns of the visible lines that are part of a match
+	highlighted := make([][]bool, height)
+	for y := range highlighted {
+		if index := w.top_line + y; len(w.search_term) > 0 && index < len(w.lines) {
+			highlighted[y] = matchMask(w.lines[index], w.search_term)
+		}
+	}
+	window.DrawContents(&dimensions, func(x, y int) (rune, tcell.Style) {
+		if y == height-1 {
+			if w.is_typing {
+				return w.search_box.Style()(x)
+			}
+			if x < len(status) {
+				return status[x], tcell.StyleDefault.Reverse(true)
+			}
+			return ' ', tcell.StyleDefault.Reverse(true)
+		}
+		index := w.top_line + y
+		column := w.left_column + x
+		if index >= len(w.lines) || column >= len(w.lines[index]) {
+			return ' ', tcell.StyleDefault
+		}
+		if highlighted[y] != nil && highlighted[y][column] {
+			return w.lines[index][column], match_style
+		}
+		return w.lines[index][column], tcell.StyleDefault
+	})
+	window.GetScreen().Show()
+}
+
+func (w *PagerWindow) statusLine() []rune {
+	last_line := utils.Min(w.top_line+w.pageHeight(), len(w.lines))
+	status := fmt.Sprintf(" %s  lines %d-%d/%d", w.title, w.top_line+1, last_line, len(w.lines))
+	if w.note != "" {
+		status += "  " + w.note
+	}
+	if len(w.search_term) > 0 && w.match_line < 0 {
+		status += fmt.Sprintf("  '%s' not found", string(w.search_term))
+	}
+	return []rune(status + "  (/ search, n/N next/previous, q close)")
+}
@@ -0,0 +1,18 @@
+package window
+
+import "github.com/gdamore/tcell/v2"
+
+// MenuItem is an entry of a popup menu, `Message` is sent to the menu's receiver when the item is chosen
+type MenuItem struct {
+	Label   string
+	Message interface{}
+}
+
+// FormAction sends the message generated from the form values to the form's receiver when `Key` is pressed
+type FormAction struct {
+	Key              tcell.Key
+	KeyName          string
+	Label            string
+	MessageGenerator func(values []string) interface{}
+	KeepOpen         bool
+}
@@ -0,0 +1,143 @@
+package prompt_window
+
+import (
+	"context"
+	"dc-top/gui/elements"
+	"dc-top/gui/view/window"
+	"errors"
+	"log"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// PromptWindow asks the user for a single line of text, the message generated from it is sent to the receiver
+type PromptWindow struct {
+	window_ctx    context.Context
+	window_cancel context.CancelFunc
+
+	title             string
+	receiver          window.WindowType
+	message_generator func(value string) interface{}
+
+	dimensions_generator func() window.Dimensions
+	text_box             elements.TextBox
+	is_enabled           bool
+
+	// Up and Down browse the history, the typed text is kept while browsing it
+	history       []string
+	history_index int
+	draft         string
+}
+
+func NewPromptWindow(title string, initial_value string, history []string, receiver window.WindowType, message_generator func(value string) interface{}) PromptWindow {
+	text_box := elements.NewTextBox(
+		elements.TextDrawer("> ", tcell.StyleDefault.Foreground(tcell.ColorYellow)),
+		2,
+		tcell.StyleDefault,
+		tcell.StyleDefault.Background(tcell.ColorWhite).Foreground(tcell.ColorBlack),
+		true)
+	text_box.SetText(initial_value)
+	text_box.End()
+	return PromptWindow{
+		title:             title,
+		receiver:          receiver,
+		message_generator: message_generator,
+		dimensions_generator: func() window.Dimensions {
+			x1, y1, x2, y2 := window.PromptWindowSize()
+			return window.NewDimensions(x1, y1, x2, y2, true)
+		},
+		text_box:      text_box,
+		is_enabled:    true,
+		history:       history,
+		history_index: len(history),
+	}
+}
+
+func (w *PromptWindow) Open(view_ctx context.Context) {
+	log.Println("Opening prompt")
+	w.window_ctx, w.window_cancel = context.WithCancel(view_ctx)
+	w.drawPrompt()
+}
+
+func (w *PromptWindow) Resize() {
+	w.drawPrompt()
+}
+
+func (w *PromptWindow) KeyPress(ev tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEnter:
+		screen := window.GetScreen()
+		screen.PostEvent(window.NewReturnUpperViewEvent())
+		screen.PostEvent(window.NewMessageEvent(w.receiver, window.Prompt, w.message_generator(w.text_box.Value())))
+		return
+	case tcell.KeyEscape, tcell.KeyCtrlD:
+		window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+		return
+	case tcell.KeyUp:
+		w.browseHistory(-1)
+	case tcell.KeyDown:
+		w.browseHistory(1)
+	default:
+		w.text_box.HandleKey(&ev)
+	}
+	w.drawPrompt()
+}
+
+func (w *PromptWindow) MousePress(_ tcell.EventMouse) {}
+
+func (w *PromptWindow) HandleEvent(interface{}, window.WindowType) (interface{}, error) {
+	window.ExitIfErr(errors.New("shouldn't have gotten here"))
+	panic(1)
+}
+
+func (w *PromptWindow) Disable() {
+	log.Printf("Disable PromptWindow...")
+	w.is_enabled = false
+}
+
+func (w *PromptWindow) Enable() {
+	log.Printf("Enable PromptWindow...")
+	w.is_enabled = true
+	w.drawPrompt()
+}
+
+func (w *PromptWindow) Close() {
+	w.window_cancel()
+}
+
+func (w *PromptWindow) browseHistory(step int) {
+	new_index := w.history_index + step
+	if new_index < 0 || new_index > len(w.history) {
+		return
+	}
+	if w.history_index == len(w.history) {
+		w.draft = w.text_box.Value()
+	}
+	w.history_index = new_index
+	if new_index == len(w.history) {
+		w.text_box.SetText(w.draft)
+	} else {
+		w.text_box.SetText(w.history[new_index])
+	}
+	w.text_box.End()
+}
+
+func (w *PromptWindow) drawPrompt() {
+	if !w.is_enabled {
+		return
+	}
+	dimensions := w.dimensions_generator()
+	title := elements.TextDrawer(w.title, tcell.StyleDefault.Bold(true))
+	text_box := w.text_box.Style()
+	var drawer = func(x, y int) (rune, tcell.Style) {
+		switch y {
+		case 0:
+			return title(x)
+		case 1:
+			return text_box(x)
+		}
+		return ' ', tcell.StyleDefault
+	}
+	window.DrawContents(&dimensions, drawer)
+	window.GetScreen().Show()
+}
@@ -0,0 +1,123 @@
+package subshell_window
+
+import (
+	"bytes"
+	"dc-top/config"
+	"dc-top/docker"
+	"fmt"
+	"log"
+	"unicode"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// attachInput holds back typed bytes that may be the start of the detach sequence,
+// and edits lines locally when the container has no TTY to do it
+type attachInput struct {
+	detach_keys    []byte
+	detach_matched int
+	line           []rune
+}
+
+func newAttachInput(detach_keys []byte) attachInput {
+	return attachInput{detach_keys: detach_keys}
+}
+
+func attachBanner(attached *docker.AttachedContainer) string {
+	banner := fmt.Sprintf("[Attached, press %s to detach", config.DetachKeys())
+	if !attached.IsStdinOpen {
+		banner += ", stdin isn't open so input is ignored"
+	} else if !attached.IsTty {
+		banner += ", no TTY so input is sent line by line"
+	}
+	return "\x1b[7m" + banner + "]\x1b[0m\r\n"
+}
+
+// sendAttachInput returns true if the detach sequence was typed
+func (s *Session) sendAttachInput(encoded []byte) bool {
+	input := &s.attach
+	to_send := make([]byte, 0, len(encoded))
+	for _, b := range encoded {
+		if b == input.detach_keys[input.detach_matched] {
+			input.detach_matched++
+			if input.detach_matched == len(input.detach_keys) {
+				input.detach_matched = 0
+				return true
+			}
+			continue
+		}
+		to_send = append(to_send, input.detach_keys[:input.detach_matched]...)
+		input.detach_matched = 0
+		if b == input.detach_keys[0] {
+			input.detach_matched = 1
+			if len(input.detach_keys) == 1 {
+				input.detach_matched = 0
+				return true
+			}
+			continue
+		}
+		to_send = append(to_send, b)
+	}
+	if len(to_send) == 0 || !s.attached.IsStdinOpen {
+		return false
+	}
+	if s.attached.IsTty {
+		s.highjacked_conn.Conn.Write(to_send)
+	} else {
+		s.editLine(to_send)
+	}
+	return false
+}
+
+// editLine echoes the input and sends it once Enter is pressed, like a terminal in canonical mode
+func (s *Session) editLine(input []byte) {
+	line := &s.attach.line
+	var echo bytes.Buffer
+	for _, r := range string(input) {
+		switch {
+		case r == '\r' || r == '\n':
+			echo.WriteString("\r\n")
+			s.highjacked_conn.Conn.Write([]byte(string(*line) + "\n"))
+			*line = (*line)[:0]
+		case r == 0x7f || r == 0x08:
+			if len(*line) > 0 {
+				*line = (*line)[:len(*line)-1]
+				echo.WriteString("\b \b")
+			}
+		case r == 0x03: // Ctrl+C can't interrupt without a TTY, it only discards the line
+			*line = (*line)[:0]
+			echo.WriteString("^C\r\n")
+		case r == 0x04: // Ctrl+D closes stdin
+			s.highjacked_conn.Conn.Write([]byte(string(*line)))
+			*line = (*line)[:0]
+			s.highjacked_conn.CloseWrite()
+		case unicode.IsPrint(r):
+			*line = append(*line, r)
+			echo.WriteRune(r)
+		}
+	}
+	s.output(echo.Bytes())
+}
+
+func (s *Session) detach() {
+	log.Printf("Detaching from container '%s'", s.id)
+	s.end()
+}
+
+// multiplexedReader demultiplexes stdout and stderr of containers without a TTY
+func (s *Session) multiplexedReader() {
+	output := terminalOutput{s: s}
+	_, err := stdcopy.StdCopy(output, output, s.highjacked_conn.Reader)
+	log.Printf("Stopped drawing. got error '%v'", err)
+	s.handleExit()
+}
+
+// terminalOutput translates "\n" into "\r\n", since without a TTY nothing else does it
+type terminalOutput struct {
+	s *Session
+}
+
+func (output terminalOutput) Write(p []byte) (int, error) {
+	output.s.output(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n")))
+	return len(p), nil
+}
@@ -1,72 +1,93 @@
 package subshell_window
 
 import (
+	"dc-top/gui/view/window"
+
 	"github.com/gdamore/tcell/v2"
 )
 
-func (w *SubshellWindow) handleKeyEvent(ev *tcell.EventKey) {
-	key := ev.Key()
+// the shortcuts of the tab bar are typed after the prefix key, like in tmux, so that every other key reaches the
+// session. Typing the prefix key twice sends it to the session
+const tabPrefixKey = tcell.KeyCtrlB
 
-	switch key {
-	case tcell.KeyEnter:
-		w.highjacked_conn.Conn.Write([]byte{0xA})
-	case tcell.KeyTab:
-		w.highjacked_conn.Conn.Write([]byte{0x9})
-	case tcell.KeyBackspace:
-		w.highjacked_conn.Conn.Write([]byte{0x8}) // does nothing?
-	case tcell.KeyBackspace2:
-		w.highjacked_conn.Conn.Write([]byte{0x8})
-	case tcell.KeyDelete:
-		w.highjacked_conn.Conn.Write([]byte{27})
-		w.highjacked_conn.Conn.Write([]byte{91})
-		w.highjacked_conn.Conn.Write([]byte{51})
-		w.highjacked_conn.Conn.Write([]byte{126})
-	case tcell.KeyCtrlC:
-		w.highjacked_conn.Conn.Write([]byte{0x3})
-	case tcell.KeyCtrlD:
-		w.highjacked_conn.Conn.Write([]byte{0x4})
-	case tcell.KeyCtrlZ:
-		w.highjacked_conn.Conn.Write([]byte{0x1A})
-	case tcell.KeyCtrlR:
-		w.highjacked_conn.Conn.Write([]byte{0x12})
-	case tcell.KeyUp:
-		w.highjacked_conn.Conn.Write([]byte{27})
-		w.highjacked_conn.Conn.Write([]byte{79})
-		w.highjacked_conn.Conn.Write([]byte{65})
-	case tcell.KeyDown:
-		w.highjacked_conn.Conn.Write([]byte{27})
-		w.highjacked_conn.Conn.Write([]byte{79})
-		w.highjacked_conn.Conn.Write([]byte{66})
+// handleTabKey handles the prefix key and the shortcut after it, and returns false for keys meant for the session
+func (w *SubshellWindow) handleTabKey(ev *tcell.EventKey) bool {
+	if !w.is_prefixed {
+		if ev.Key() == tabPrefixKey {
+			w.is_prefixed = true
+			return true
+		}
+		return false
+	}
+	w.is_prefixed = false
+	_, active := sessionTabs()
+	switch ev.Key() {
+	case tabPrefixKey:
+		return false
 	case tcell.KeyLeft:
-		w.highjacked_conn.Conn.Write([]byte{27})
-		w.highjacked_conn.Conn.Write([]byte{79})
-		w.highjacked_conn.Conn.Write([]byte{68})
+		switchSession(active-1, true)
+		return true
 	case tcell.KeyRight:
-		w.highjacked_conn.Conn.Write([]byte{27})
-		w.highjacked_conn.Conn.Write([]byte{79})
-		w.highjacked_conn.Conn.Write([]byte{67})
-	case tcell.KeyPgUp:
-		w.highjacked_conn.Conn.Write([]byte{27})
-		w.highjacked_conn.Conn.Write([]byte{91})
-		w.highjacked_conn.Conn.Write([]byte{53})
-		w.highjacked_conn.Conn.Write([]byte{126})
-	case tcell.KeyPgDn:
-		w.highjacked_conn.Conn.Write([]byte{27})
-		w.highjacked_conn.Conn.Write([]byte{91})
-		w.highjacked_conn.Conn.Write([]byte{54})
-		w.highjacked_conn.Conn.Write([]byte{126})
-	case tcell.KeyHome:
-		w.highjacked_conn.Conn.Write([]byte{27})
-		w.highjacked_conn.Conn.Write([]byte{91})
-		w.highjacked_conn.Conn.Write([]byte{49})
-		w.highjacked_conn.Conn.Write([]byte{126})
-	case tcell.KeyEnd:
-		w.highjacked_conn.Conn.Write([]byte{27})
-		w.highjacked_conn.Conn.Write([]byte{91})
-		w.highjacked_conn.Conn.Write([]byte{52})
-		w.highjacked_conn.Conn.Write([]byte{126})
+		switchSession(active+1, true)
+		return true
 	case tcell.KeyRune:
-		bytes_written := []byte(string(ev.Rune()))
-		w.highjacked_conn.Conn.Write(bytes_written)
+		switch r := ev.Rune(); {
+		case r >= '1' && r <= '9':
+			switchSession(int(r-'1'), false)
+			return true
+		case r == 'q':
+			// the sessions keep running in the background, 'T' in the containers window resumes them
+			window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+			return true
+		case r == 'r':
+			if s := activeSession(); s != nil && !s.hasExited() {
+				s.toggleRecording()
+				notifySessionsChanged()
+			}
+			return true
+		case r == 'w':
+			if s := activeSession(); s != nil {
+				s.end()
+			}
+			return true
+		}
+	}
+	// unknown shortcuts are dropped
+	return true
+}
+
+func (s *Session) handleKeyEvent(ev *tcell.EventKey) {
+	if s.getNotice() != "" {
+		s.clearNotice()
+		notifySessionsChanged()
+	}
+	// Shift+PgUp/PgDn browse the scrollback like in most terminals, and aren't sent to the shell
+	if ev.Modifiers()&tcell.ModShift != 0 {
+		_, height := s.terminal.Size()
+		switch ev.Key() {
+		case tcell.KeyPgUp:
+			s.terminal.ScrollBack(height / 2)
+			notifySessionsChanged()
+			return
+		case tcell.KeyPgDn:
+			s.terminal.ScrollBack(-height / 2)
+			notifySessionsChanged()
+			return
+		}
+	}
+	if s.terminal.IsScrolled() {
+		s.terminal.ResetScroll()
+		notifySessionsChanged()
+	}
+	encoded := s.terminal.EncodeKey(ev)
+	if len(encoded) == 0 {
+		return
+	}
+	if s.kind == attachSession {
+		if s.sendAttachInput(encoded) {
+			s.detach()
+		}
+		return
 	}
+	s.write(encoded)
 }
@@ -0,0 +1,106 @@
+package subshell_window
+
+import (
+	"dc-top/config"
+	"dc-top/gui/elements"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// toggleRecording starts recording the session to a new asciicast file, or saves the current recording
+func (s *Session) toggleRecording() {
+	s.recording_lock.Lock()
+	defer s.recording_lock.Unlock()
+	if s.recorder != nil {
+		s.closeRecorder()
+		return
+	}
+	width, height := s.terminal.Size()
+	path, err := s.recordingPath()
+	if err == nil {
+		s.recorder, err = elements.NewCastRecorder(path, elements.CastHeader{
+			Width:  width,
+			Height: height,
+			Title:  s.title(),
+			Env:    map[string]string{"TERM": "xterm-256color", "SHELL": strings.Join(s.exec.Command, " ")},
+		})
+	}
+	if err != nil {
+		log.Printf("Failed to start recording: %s", err)
+		s.notice = fmt.Sprintf("Failed to start recording: %s", err)
+		return
+	}
+	// the recording starts from what is already on the screen
+	s.recorder.Output(screenContents(s.terminal.Snapshot(), height))
+	s.notice = fmt.Sprintf("Recording to %s", path)
+	log.Printf("Recording session in container '%s' to %s", s.id, path)
+}
+
+func (s *Session) stopRecording() {
+	s.recording_lock.Lock()
+	defer s.recording_lock.Unlock()
+	if s.recorder != nil {
+		s.closeRecorder()
+	}
+}
+
+func (s *Session) closeRecorder() {
+	if err := s.recorder.Close(); err != nil {
+		s.notice = fmt.Sprintf("Failed to save recording: %s", err)
+	} else {
+		s.notice = "Recording saved, press 'P' in the containers list to replay it"
+	}
+	s.recorder = nil
+}
+
+func (s *Session) isRecording() bool {
+	s.recording_lock.Lock()
+	defer s.recording_lock.Unlock()
+	return s.recorder != nil
+}
+
+func (s *Session) getNotice() string {
+	s.recording_lock.Lock()
+	defer s.recording_lock.Unlock()
+	return s.notice
+}
+
+func (s *Session) clearNotice() {
+	s.recording_lock.Lock()
+	defer s.recording_lock.Unlock()
+	s.notice = ""
+}
+
+func (s *Session) recordingPath() (string, error) {
+	dir := config.RecordingsDir()
+	if dir == "" {
+		return "", errors.New("no recordings directory")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == os.PathSeparator {
+			return '_'
+		}
+		return r
+	}, s.name)
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.cast", name, time.Now().Format("20060102-150405"))), nil
+}
+
+// screenContents redraws the text of the screen, without styles
+func screenContents(snapshot elements.TerminalSnapshot, height int) []byte {
+	lines := make([]string, height)
+	for y := range lines {
+		lines[y] = snapshot.Line(y)
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return []byte(fmt.Sprintf("\x1b[H\x1b[2J%s\x1b[%d;%dH", strings.Join(lines, "\r\n"), snapshot.CursorY+1, snapshot.CursorX+1))
+}
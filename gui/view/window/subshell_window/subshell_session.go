@@ -0,0 +1,362 @@
+package subshell_window
+
+import (
+	"context"
+	"dc-top/config"
+	"dc-top/docker"
+	"dc-top/gui/elements"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+)
+
+type sessionKind uint8
+
+const (
+	execSession sessionKind = iota
+	debugSession
+	attachSession
+)
+
+// Session is a connection to a process in a container. Sessions keep running in the background
+// when the subshell view is closed, until the process exits or the session is closed from its tab
+type Session struct {
+	session_ctx    context.Context
+	session_cancel context.CancelFunc
+
+	kind            sessionKind
+	id              string
+	name            string
+	exec            docker.ExecSpec
+	exec_id         string
+	highjacked_conn *types.HijackedResponse
+	terminal        *elements.Terminal
+
+	sidecar_id string
+	attached   *docker.AttachedContainer
+	attach     attachInput
+	// commands other than shells are kept open after they exit, so their output can be read
+	wait_on_exit bool
+	exited_ch    chan interface{}
+
+	recorder       *elements.CastRecorder
+	recording_lock sync.Mutex
+	// notice is shown in the tab bar until the next key press
+	notice string
+}
+
+func NewExecSession(id string, exec docker.ExecSpec) *Session {
+	return &Session{
+		kind:         execSession,
+		id:           id,
+		exec:         exec,
+		wait_on_exit: len(exec.Command) > 0,
+		exited_ch:    make(chan interface{}),
+	}
+}
+
+// NewDebugSession opens a shell in a sidecar container that shares the namespaces of the container
+func NewDebugSession(id string) *Session {
+	s := NewExecSession(id, docker.ExecSpec{})
+	s.kind = debugSession
+	return s
+}
+
+// NewAttachSession attaches to the stdio of the container's main process, like `docker attach`
+func NewAttachSession(id string) *Session {
+	s := NewExecSession(id, docker.ExecSpec{})
+	s.kind = attachSession
+	s.wait_on_exit = true
+	s.attach = newAttachInput(config.DetachKeySequence())
+	return s
+}
+
+// start connects to the container and adds the session to the open sessions. It may pull the image of a debug
+// sidecar, so it's called outside of the GUI's goroutine, on_progress gets the progress of the pull
+func (s *Session) start(bg_context context.Context, width, height int, on_progress func(string)) error {
+	var err error
+	s.session_ctx, s.session_cancel = context.WithCancel(bg_context)
+	s.name = strings.TrimPrefix(docker.InspectContainerNoPanic(s.session_ctx, s.id).Name, "/")
+
+	switch s.kind {
+	case execSession:
+		s.highjacked_conn, s.exec_id, err = s.openExec()
+	case debugSession:
+		s.highjacked_conn, s.sidecar_id, err = docker.OpenDebugShell(s.session_ctx, s.id, config.DebugImage(), config.DebugShell(), on_progress)
+	case attachSession:
+		s.attached, err = docker.AttachContainer(s.session_ctx, s.id)
+		if err == nil {
+			s.highjacked_conn = s.attached.Conn
+		}
+	}
+	if err != nil {
+		s.highjacked_conn = nil
+		s.session_cancel()
+		switch s.kind {
+		case execSession:
+			return fmt.Errorf("failed to open shell (%s), press 'd' to debug the container from a sidecar instead", err)
+		case debugSession:
+			return fmt.Errorf("failed to start debug sidecar from %s: %s", config.DebugImage(), err)
+		default:
+			return fmt.Errorf("failed to attach: %s", err)
+		}
+	}
+
+	s.terminal = elements.NewTerminal(width, height)
+	if s.kind != attachSession || s.attached.IsTty {
+		s.terminal.SetResponder(func(response []byte) {
+			s.highjacked_conn.Conn.Write(response)
+		})
+	}
+	if s.kind == attachSession {
+		s.terminal.Write([]byte(attachBanner(s.attached)))
+	}
+	s.resizeTTY(width, height)
+	addSession(s)
+	go s.reader()
+	return nil
+}
+
+func (s *Session) close() {
+	s.session_cancel()
+	s.stopRecording()
+	if s.highjacked_conn != nil {
+		s.highjacked_conn.Close()
+	}
+	if s.sidecar_id != "" {
+		go docker.RemoveDebugSidecar(s.sidecar_id)
+	}
+}
+
+// end closes the session and removes it from its tab
+func (s *Session) end() {
+	removeSession(s)
+	s.close()
+}
+
+func (s *Session) hasExited() bool {
+	select {
+	case <-s.exited_ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// title is the label of the session's tab
+func (s *Session) title() string {
+	title := s.name
+	switch s.kind {
+	case execSession:
+		if len(s.exec.Command) > 0 {
+			title += " " + s.exec.Command[0]
+		}
+	case debugSession:
+		title += " (debug)"
+	case attachSession:
+		title += " (attached)"
+	}
+	if s.hasExited() {
+		title += " [exited]"
+	}
+	return title
+}
+
+// resize changes the size of the terminal and the TTY, if it doesn't have that size already
+func (s *Session) resize(width, height int) {
+	if current_width, current_height := s.terminal.Size(); current_width == width && current_height == height {
+		return
+	}
+	s.terminal.Resize(width, height)
+	s.resizeTTY(width, height)
+	s.recording_lock.Lock()
+	if s.recorder != nil {
+		s.recorder.Resize(width, height)
+	}
+	s.recording_lock.Unlock()
+}
+
+// resizeTTY makes full screen programs inside the container use the size of the window
+func (s *Session) resizeTTY(width, height int) {
+	var err error
+	switch s.kind {
+	case execSession:
+		err = docker.ResizeExecTTY(s.session_ctx, s.exec_id, width, height)
+	case debugSession:
+		err = docker.ResizeContainerTTY(s.session_ctx, s.sidecar_id, width, height)
+	case attachSession:
+		if s.attached.IsTty {
+			err = docker.ResizeContainerTTY(s.session_ctx, s.id, width, height)
+		}
+	}
+	if err != nil {
+		log.Printf("Failed to resize TTY to %dx%d: %s", width, height, err)
+	}
+}
+
+func (s *Session) openExec() (*types.HijackedResponse, string, error) {
+	exec := s.exec
+	if len(exec.Command) == 0 {
+		shell, err := docker.DetectShell(s.session_ctx, s.id, config.Shells())
+		if err != nil {
+			return nil, "", err
+		}
+		exec.Command = []string{shell}
+		s.exec.Command = exec.Command
+	}
+	return docker.OpenExec(s.session_ctx, s.id, exec)
+}
+
+func (s *Session) reader() {
+	if s.kind == attachSession && !s.attached.IsTty {
+		s.multiplexedReader()
+		return
+	}
+	var buff [4096]byte
+	for {
+		n, err := s.highjacked_conn.Reader.Read(buff[:])
+		if n > 0 {
+			s.output(buff[:n])
+		}
+		if err != nil {
+			log.Printf("Session in container '%s' stopped. got error '%s'", s.id, err)
+			s.handleExit()
+			return
+		}
+		select {
+		case <-s.session_ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+func (s *Session) handleExit() {
+	if s.session_ctx.Err() != nil {
+		// the session is already being closed
+		return
+	}
+	if !s.wait_on_exit {
+		s.end()
+		return
+	}
+	if s.kind == attachSession {
+		s.output([]byte("\r\n\x1b[7m[The container's output ended, press any key to close]\x1b[0m"))
+	} else {
+		exit_code, err := docker.ExecExitCode(s.session_ctx, s.exec_id)
+		if err != nil {
+			s.end()
+			return
+		}
+		s.output([]byte(fmt.Sprintf("\r\n\x1b[7m[Process exited with code %d, press any key to close]\x1b[0m", exit_code)))
+	}
+	close(s.exited_ch)
+	notifySessionsChanged()
+}
+
+// output writes to the terminal and to the recording, if the session is being recorded
+func (s *Session) output(p []byte) {
+	s.terminal.Write(p)
+	s.recording_lock.Lock()
+	if s.recorder != nil {
+		s.recorder.Output(p)
+	}
+	s.recording_lock.Unlock()
+	notifySessionsChanged()
+}
+
+func (s *Session) write(input []byte) {
+	s.highjacked_conn.Conn.Write(input)
+}
+
+var (
+	sessions       []*Session
+	active_session int
+	sessions_lock  sync.Mutex
+	// sessions_changed_ch wakes the subshell window when a session has new output or ended
+	sessions_changed_ch = make(chan interface{}, 1)
+)
+
+func HasSessions() bool {
+	sessions_lock.Lock()
+	defer sessions_lock.Unlock()
+	return len(sessions) > 0
+}
+
+// CloseSessions closes every session, including the ones running in the background
+func CloseSessions() {
+	sessions_lock.Lock()
+	to_close := sessions
+	sessions = nil
+	sessions_lock.Unlock()
+	for _, s := range to_close {
+		s.close()
+	}
+}
+
+func addSession(s *Session) {
+	sessions_lock.Lock()
+	defer sessions_lock.Unlock()
+	sessions = append(sessions, s)
+	active_session = len(sessions) - 1
+	notifySessionsChanged()
+}
+
+func removeSession(s *Session) {
+	sessions_lock.Lock()
+	defer sessions_lock.Unlock()
+	for i, open := range sessions {
+		if open == s {
+			sessions = append(sessions[:i], sessions[i+1:]...)
+			if active_session > i || active_session == len(sessions) {
+				active_session--
+			}
+			break
+		}
+	}
+	if active_session < 0 {
+		active_session = 0
+	}
+	notifySessionsChanged()
+}
+
+func activeSession() *Session {
+	sessions_lock.Lock()
+	defer sessions_lock.Unlock()
+	if len(sessions) == 0 {
+		return nil
+	}
+	return sessions[active_session]
+}
+
+// switchSession activates the session at index, wrapping around when cycling through tabs
+func switchSession(index int, wrap bool) {
+	sessions_lock.Lock()
+	defer sessions_lock.Unlock()
+	if len(sessions) == 0 {
+		return
+	}
+	if wrap {
+		index = (index%len(sessions) + len(sessions)) % len(sessions)
+	} else if index >= len(sessions) {
+		return
+	}
+	active_session = index
+	notifySessionsChanged()
+}
+
+func sessionTabs() ([]*Session, int) {
+	sessions_lock.Lock()
+	defer sessions_lock.Unlock()
+	return append([]*Session(nil), sessions...), active_session
+}
+
+func notifySessionsChanged() {
+	select {
+	case sessions_changed_ch <- nil:
+	default:
+	}
+}
@@ -2,62 +2,88 @@ package subshell_window
 
 import (
 	"context"
-	"dc-top/docker"
 	"dc-top/gui/view/window"
+	"dc-top/gui/view/window/bar_window"
 	"fmt"
 	"log"
 
-	"github.com/docker/docker/api/types"
 	"github.com/gdamore/tcell/v2"
 )
 
+// SubshellWindow shows the open sessions as tabs, with the active session below the tab bar
 type SubshellWindow struct {
+	bg_context    context.Context
 	window_ctx    context.Context
 	window_cancel context.CancelFunc
 
 	is_enabled           bool
 	dimensions_generator func() window.Dimensions
 	resize_ch            chan interface{}
-	draw_request_ch      chan interface{}
 	enable_toggle        chan bool
 
-	id              string
-	highjacked_conn *types.HijackedResponse
+	// new_session is started when the window opens, the window only resumes the open sessions without it
+	new_session *Session
+	// shown until the new session started
+	starting_status string
+	// the prefix key of the tab shortcuts was typed, only used from KeyPress
+	is_prefixed bool
 }
 
-func NewSubshellWindow(id string) SubshellWindow {
+func NewSubshellWindow(bg_context context.Context, new_session *Session) SubshellWindow {
 	return SubshellWindow{
+		bg_context: bg_context,
 		is_enabled: true,
 		dimensions_generator: func() window.Dimensions {
 			w, h := window.GetScreen().Size()
 			return window.NewDimensions(0, 0, w-1, h-1, false)
 		},
-		resize_ch:       make(chan interface{}),
-		draw_request_ch: make(chan interface{}),
-		enable_toggle:   make(chan bool),
-		id:              id,
+		resize_ch:     make(chan interface{}),
+		enable_toggle: make(chan bool),
+		new_session:   new_session,
 	}
 }
 
 func (w *SubshellWindow) Open(view_ctx context.Context) {
-	var err error
 	w.window_ctx, w.window_cancel = context.WithCancel(view_ctx)
+	go w.main()
+}
 
-	w.highjacked_conn, err = docker.OpenShell(w.id, w.window_ctx, "sh")
-	if err != nil {
-		log.Println("Failed to open shell")
-		return
+// startSession starts the new session, sending the progress and then the result of starting it
+func (w *SubshellWindow) startSession(progress_ch chan<- string, started_ch chan<- error) {
+	terminal_dimensions := w.terminalDimensions()
+	err := w.new_session.start(w.bg_context, window.Width(&terminal_dimensions), window.Height(&terminal_dimensions),
+		func(status string) {
+			select {
+			case progress_ch <- status:
+			case <-w.window_ctx.Done():
+			}
+		})
+	select {
+	case started_ch <- err:
+	case <-w.window_ctx.Done():
 	}
-
-	go w.main()
 }
 
 func (w *SubshellWindow) Resize() {
-	w.resize_ch <- nil
+	select {
+	case w.resize_ch <- nil:
+	case <-w.window_ctx.Done():
+	}
 }
 
 func (w *SubshellWindow) KeyPress(ev tcell.EventKey) {
-	w.handleKeyEvent(&ev)
+	if w.window_ctx.Err() != nil || w.handleTabKey(&ev) {
+		return
+	}
+	s := activeSession()
+	if s == nil {
+		return
+	}
+	if s.hasExited() {
+		s.end()
+		return
+	}
+	s.handleKeyEvent(&ev)
 }
 
 func (w *SubshellWindow) MousePress(_ tcell.EventMouse) {}
@@ -68,44 +94,163 @@ func (w *SubshellWindow) HandleEvent(interface{}, window.WindowType) (interface{
 
 func (w *SubshellWindow) Disable() {
 	log.Printf("Disable SubshellWindow...")
-	w.enable_toggle <- false
+	select {
+	case w.enable_toggle <- false:
+	case <-w.window_ctx.Done():
+	}
 }
 
 func (w *SubshellWindow) Enable() {
 	log.Printf("Enable SubshellWindow...")
-	w.enable_toggle <- true
+	select {
+	case w.enable_toggle <- true:
+	case <-w.window_ctx.Done():
+	}
 }
 
+// Close leaves the sessions running in the background
 func (w *SubshellWindow) Close() {
 	w.window_cancel()
-	w.highjacked_conn.Close()
+	window.GetScreen().HideCursor()
 }
 
 func (w *SubshellWindow) main() {
 	window.GetScreen().Clear()
-	window.GetScreen().Show()
-	fmt.Print("\x1B[H") // clear screen escape character sequence
-	go w.shellReader()
-}
-
-func (w *SubshellWindow) shellReader() {
-	defer window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
-
+	progress_ch := make(chan string)
+	started_ch := make(chan error)
+	if w.new_session != nil {
+		w.starting_status = "Starting session..."
+		go w.startSession(progress_ch, started_ch)
+	}
+	w.draw()
 	for {
 		select {
+		case status := <-progress_ch:
+			w.starting_status = status
+			w.draw()
+		case err := <-started_ch:
+			w.starting_status = ""
+			if err != nil {
+				log.Printf("Failed to open session: %s", err)
+				w.window_cancel()
+				bar_window.Err([]rune(err.Error()))
+				window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+				return
+			}
+			w.draw()
+		case <-sessions_changed_ch:
+			w.draw()
+		case <-w.resize_ch:
+			w.draw()
+		case is_enabled := <-w.enable_toggle:
+			w.is_enabled = is_enabled
+			w.draw()
 		case <-w.window_ctx.Done():
 			return
-		default:
 		}
-		var buff [1024]byte
-		n, err := w.highjacked_conn.Reader.Read(buff[:])
-		if err != nil {
-			log.Printf("Stopped drawing. got error '%s'", err)
-			return
+	}
+}
+
+func (w *SubshellWindow) terminalDimensions() window.Dimensions {
+	dimensions := w.dimensions_generator()
+	return window.NewDimensions(dimensions.LeftX, dimensions.TopY+1, dimensions.RightX, dimensions.ButtomY, false)
+}
+
+func (w *SubshellWindow) draw() {
+	if !w.is_enabled {
+		return
+	}
+	screen := window.GetScreen()
+	if w.starting_status != "" {
+		dimensions := w.dimensions_generator()
+		status := []rune(" " + w.starting_status)
+		window.DrawContents(&dimensions, func(x, y int) (rune, tcell.Style) {
+			if y == 0 && x < len(status) {
+				return status[x], tcell.StyleDefault.Foreground(tcell.ColorYellow)
+			}
+			return ' ', tcell.StyleDefault
+		})
+		screen.Show()
+		return
+	}
+	tabs, active := sessionTabs()
+	if len(tabs) == 0 {
+		// the last session ended
+		w.window_cancel()
+		screen.HideCursor()
+		screen.PostEvent(window.NewReturnUpperViewEvent())
+		return
+	}
+	session := tabs[active]
+
+	dimensions := w.dimensions_generator()
+	tab_bar := tabBar(tabs, active, window.Width(&dimensions))
+	window.DrawContents(&window.Dimensions{
+		LeftX: dimensions.LeftX, RightX: dimensions.RightX, TopY: dimensions.TopY, ButtomY: dimensions.TopY,
+	}, func(x, _ int) (rune, tcell.Style) {
+		if x < len(tab_bar) {
+			return tab_bar[x].r, tab_bar[x].style
 		}
-		new_output := string(buff[:n])
-		if w.is_enabled {
-			fmt.Print(new_output)
+		return ' ', tabBarStyle
+	})
+
+	terminal_dimensions := w.terminalDimensions()
+	session.resize(window.Width(&terminal_dimensions), window.Height(&terminal_dimensions))
+	snapshot := session.terminal.Snapshot()
+	window.DrawContents(&terminal_dimensions, snapshot.Cell)
+	if snapshot.CursorVisible {
+		screen.ShowCursor(terminal_dimensions.LeftX+snapshot.CursorX, terminal_dimensions.TopY+snapshot.CursorY)
+	} else {
+		screen.HideCursor()
+	}
+	screen.Show()
+}
+
+type tabCell struct {
+	r     rune
+	style tcell.Style
+}
+
+var (
+	tabBarStyle    = tcell.StyleDefault.Background(tcell.ColorDarkSlateGray).Foreground(tcell.ColorWhite)
+	activeTabStyle = tcell.StyleDefault.Background(tcell.ColorWhite).Foreground(tcell.ColorBlack).Bold(true)
+	recordingStyle = tcell.StyleDefault.Background(tcell.ColorDarkRed).Foreground(tcell.ColorWhite).Bold(true)
+)
+
+const tabBarHint = " Ctrl+B then ←/→ switch, r record, q back, w close "
+
+// tabBar lays out the tabs, and the shortcuts on the right if there's room for them
+func tabBar(tabs []*Session, active, width int) []tabCell {
+	cells := make([]tabCell, 0, width)
+	for i, s := range tabs {
+		style := tabBarStyle
+		if i == active {
+			style = activeTabStyle
+		}
+		for _, r := range fmt.Sprintf(" %d:%s ", i+1, s.title()) {
+			cells = append(cells, tabCell{r, style})
+		}
+		if s.isRecording() {
+			for _, r := range "●REC " {
+				cells = append(cells, tabCell{r, recordingStyle})
+			}
 		}
+		cells = append(cells, tabCell{'│', tabBarStyle})
+	}
+	hint := tabBarHint
+	if notice := tabs[active].getNotice(); notice != "" {
+		hint = " " + notice + " "
+	}
+	if hint_start := width - len([]rune(hint)); len(cells) <= hint_start {
+		for len(cells) < hint_start {
+			cells = append(cells, tabCell{' ', tabBarStyle})
+		}
+		for _, r := range hint {
+			cells = append(cells, tabCell{r, tabBarStyle})
+		}
+	}
+	if len(cells) > width {
+		cells = cells[:width]
 	}
+	return cells
 }
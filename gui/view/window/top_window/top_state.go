@@ -0,0 +1,185 @@
+package top_window
+
+import (
+	"dc-top/docker"
+	"dc-top/gui/view/window"
+	"dc-top/utils"
+	"fmt"
+	"sort"
+
+	"github.com/docker/go-units"
+	"github.com/gdamore/tcell/v2"
+)
+
+type processColumn uint8
+
+const (
+	pidColumn processColumn = iota
+	userColumn
+	cpuColumn
+	memoryColumn
+	rssColumn
+	commandColumn
+)
+
+var columnTitles = []string{"PID", "USER", "%CPU", "%MEM", "RSS", "COMMAND"}
+
+type topState struct {
+	processes []docker.ContainerProcess
+	// the focus follows the process across refreshes and sorting
+	focused_pid int
+	focused     int
+	top_row     int
+	sort_column processColumn
+	is_reverse  bool
+	status      string
+}
+
+func newTopState() *topState {
+	return &topState{sort_column: cpuColumn, is_reverse: true}
+}
+
+func (state *topState) setProcesses(processes []docker.ContainerProcess) {
+	state.processes = processes
+	state.sort()
+}
+
+// sortBy sorts by the column, choosing the sorted column again reverses the order
+func (state *topState) sortBy(column processColumn) {
+	if column == state.sort_column {
+		state.is_reverse = !state.is_reverse
+	} else {
+		state.sort_column = column
+		// the busiest processes are the interesting ones
+		state.is_reverse = column == cpuColumn || column == memoryColumn || column == rssColumn
+	}
+	state.sort()
+}
+
+func (state *topState) sort() {
+	less := func(a, b *docker.ContainerProcess) bool {
+		switch state.sort_column {
+		case userColumn:
+			return a.User < b.User
+		case cpuColumn:
+			return a.CPU < b.CPU
+		case memoryColumn:
+			return a.Memory < b.Memory
+		case rssColumn:
+			return a.RSS < b.RSS
+		case commandColumn:
+			return a.Command < b.Command
+		}
+		return a.PID < b.PID
+	}
+	sort.SliceStable(state.processes, func(i, j int) bool {
+		if state.is_reverse {
+			return less(&state.processes[j], &state.processes[i])
+		}
+		return less(&state.processes[i], &state.processes[j])
+	})
+	state.focused = 0
+	for i, process := range state.processes {
+		if process.PID == state.focused_pid {
+			state.focused = i
+			break
+		}
+	}
+	state.moveFocus(0)
+}
+
+func (state *topState) moveFocus(delta int) {
+	state.focused = utils.Min(utils.Max(state.focused+delta, 0), utils.Max(len(state.processes)-1, 0))
+	if process, ok := state.focusedProcess(); ok {
+		state.focused_pid = process.PID
+	}
+}
+
+func (state *topState) focusedProcess() (docker.ContainerProcess, bool) {
+	if state.focused < 0 || state.focused >= len(state.processes) {
+		return docker.ContainerProcess{}, false
+	}
+	return state.processes[state.focused], true
+}
+
+const rowFormat = " %7s  %-10s %6s %6s %9s  %s"
+
+func formatProcess(process *docker.ContainerProcess) []rune {
+	return []rune(fmt.Sprintf(rowFormat,
+		fmt.Sprint(process.PID),
+		truncate(process.User, 10),
+		fmt.Sprintf("%.1f", process.CPU),
+		fmt.Sprintf("%.1f", process.Memory),
+		units.BytesSize(float64(process.RSS)),
+		process.Command))
+}
+
+// truncate keeps long user names from shifting the columns after them
+func truncate(text string, width int) string {
+	if runes := []rune(text); len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return text
+}
+
+func (state *topState) header() []rune {
+	titles := make([]interface{}, len(columnTitles))
+	for i, title := range columnTitles {
+		if processColumn(i) == state.sort_column {
+			if state.is_reverse {
+				title += "↓"
+			} else {
+				title += "↑"
+			}
+		}
+		titles[i] = title
+	}
+	return []rune(fmt.Sprintf(rowFormat, titles...))
+}
+
+func (w *TopWindow) draw(state *topState) {
+	dimensions := w.dimensions_generator()
+	height := window.Height(&dimensions)
+	page := utils.Max(height-3, 1)
+	if state.focused < state.top_row {
+		state.top_row = state.focused
+	} else if state.focused >= state.top_row+page {
+		state.top_row = state.focused - page + 1
+	}
+	state.top_row = utils.Max(utils.Min(state.top_row, len(state.processes)-page), 0)
+
+	title := []rune(fmt.Sprintf(" Processes of %s (%d)", w.name, len(state.processes)))
+	header := state.header()
+	status := []rune(" " + state.status)
+	if state.status == "" {
+		status = []rune(fmt.Sprintf(" 1-%d sort by column, k send a signal, r refresh, q close", len(columnTitles)))
+	}
+	rows := make([][]rune, page)
+	for y := range rows {
+		if index := state.top_row + y; index < len(state.processes) {
+			rows[y] = formatProcess(&state.processes[index])
+		}
+	}
+	window.DrawContents(&dimensions, func(x, y int) (rune, tcell.Style) {
+		var line []rune
+		style := tcell.StyleDefault
+		switch {
+		case y == 0:
+			line, style = title, style.Bold(true)
+		case y == 1:
+			line, style = header, style.Reverse(true)
+		case y == height-1:
+			line, style = status, style.Foreground(tcell.ColorYellow)
+		default:
+			line = rows[y-2]
+			if state.top_row+y-2 == state.focused && line != nil {
+				style = style.Background(tcell.ColorDarkBlue)
+			}
+		}
+		if x < len(line) {
+			return line[x], style
+		}
+		return ' ', style
+	})
+	window.GetScreen().Show()
+}
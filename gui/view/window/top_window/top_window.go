@@ -0,0 +1,259 @@
+package top_window
+
+import (
+	"context"
+	"dc-top/config"
+	"dc-top/docker"
+	"dc-top/gui/view/window"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const refreshInterval = 2 * time.Second
+
+var processSignals = []string{"SIGTERM", "SIGINT", "SIGHUP", "SIGQUIT", "SIGUSR1", "SIGUSR2", "SIGKILL"}
+
+// signalRequest is sent by the signal menu
+type signalRequest struct {
+	process docker.ContainerProcess
+	signal  string
+}
+
+// TopWindow is a process table of a running container, refreshed periodically
+type TopWindow struct {
+	window_ctx    context.Context
+	window_cancel context.CancelFunc
+
+	id   string
+	name string
+
+	dimensions_generator func() window.Dimensions
+	keyboard_ch          chan tcell.EventKey
+	resize_ch            chan interface{}
+	enable_toggle        chan bool
+	action_ch            chan interface{}
+}
+
+func NewTopWindow(id string) TopWindow {
+	return TopWindow{
+		id: id,
+		dimensions_generator: func() window.Dimensions {
+			w, h := window.GetScreen().Size()
+			return window.NewDimensions(0, 0, w-1, h-1, false)
+		},
+		keyboard_ch:   make(chan tcell.EventKey),
+		resize_ch:     make(chan interface{}),
+		enable_toggle: make(chan bool),
+		action_ch:     make(chan interface{}),
+	}
+}
+
+func (w *TopWindow) Open(view_ctx context.Context) {
+	w.window_ctx, w.window_cancel = context.WithCancel(view_ctx)
+	w.name = strings.TrimPrefix(docker.InspectContainerNoPanic(w.window_ctx, w.id).Name, "/")
+	go w.main()
+}
+
+func (w *TopWindow) Resize() {
+	select {
+	case w.resize_ch <- nil:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *TopWindow) KeyPress(ev tcell.EventKey) {
+	select {
+	case w.keyboard_ch <- ev:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *TopWindow) MousePress(_ tcell.EventMouse) {}
+
+func (w *TopWindow) HandleEvent(ev interface{}, sender window.WindowType) (interface{}, error) {
+	switch ev := ev.(type) {
+	case signalRequest:
+		select {
+		case w.action_ch <- ev:
+		case <-w.window_ctx.Done():
+		}
+	default:
+		log.Printf("Got unknown event in top window %T", ev)
+	}
+	return nil, nil
+}
+
+func (w *TopWindow) Disable() {
+	log.Printf("Disable TopWindow...")
+	select {
+	case w.enable_toggle <- false:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *TopWindow) Enable() {
+	log.Printf("Enable TopWindow...")
+	select {
+	case w.enable_toggle <- true:
+	case <-w.window_ctx.Done():
+	}
+}
+
+func (w *TopWindow) Close() {
+	w.window_cancel()
+}
+
+type processesListed struct {
+	processes []docker.ContainerProcess
+	err       error
+}
+
+type signalSent struct {
+	request signalRequest
+	err     error
+}
+
+func (w *TopWindow) main() {
+	window.GetScreen().Clear()
+	state := newTopState()
+	results_ch := make(chan interface{})
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	is_enabled := true
+	w.refresh(results_ch)
+	for {
+		if is_enabled {
+			w.draw(state)
+		}
+		select {
+		case ev := <-w.keyboard_ch:
+			if w.handleKeyPress(state, &ev, results_ch) {
+				return
+			}
+		case <-ticker.C:
+			if is_enabled {
+				w.refresh(results_ch)
+			}
+		case result := <-results_ch:
+			switch result := result.(type) {
+			case processesListed:
+				if result.err != nil {
+					state.status = fmt.Sprintf("Failed to list processes: %s", result.err)
+				} else {
+					state.setProcesses(result.processes)
+				}
+			case signalSent:
+				if result.err != nil {
+					state.status = fmt.Sprintf("Failed to send %s to %d: %s", result.request.signal, result.request.process.PID, result.err)
+				} else {
+					state.status = fmt.Sprintf("Sent %s to %d", result.request.signal, result.request.process.PID)
+					w.refresh(results_ch)
+				}
+			}
+		case action := <-w.action_ch:
+			if request, ok := action.(signalRequest); ok {
+				w.sendSignal(state, request, results_ch)
+			}
+		case <-w.resize_ch:
+			window.GetScreen().Clear()
+		case is_enabled = <-w.enable_toggle:
+		case <-w.window_ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *TopWindow) refresh(results_ch chan<- interface{}) {
+	go func() {
+		processes, err := docker.ContainerTop(w.window_ctx, w.id)
+		select {
+		case results_ch <- processesListed{processes, err}:
+		case <-w.window_ctx.Done():
+		}
+	}()
+}
+
+func (w *TopWindow) sendSignal(state *topState, request signalRequest, results_ch chan<- interface{}) {
+	state.status = fmt.Sprintf("Sending %s to %d...", request.signal, request.process.PID)
+	go func() {
+		err := docker.SignalProcess(w.window_ctx, w.id, request.process, request.signal)
+		select {
+		case results_ch <- signalSent{request, err}:
+		case <-w.window_ctx.Done():
+		}
+	}()
+}
+
+// handleKeyPress returns true when the window is closed
+func (w *TopWindow) handleKeyPress(state *topState, ev *tcell.EventKey, results_ch chan<- interface{}) bool {
+	page := w.pageHeight()
+	switch ev.Key() {
+	case tcell.KeyUp:
+		state.moveFocus(-1)
+	case tcell.KeyDown:
+		state.moveFocus(1)
+	case tcell.KeyPgUp:
+		state.moveFocus(-page)
+	case tcell.KeyPgDn:
+		state.moveFocus(page)
+	case tcell.KeyHome:
+		state.moveFocus(-len(state.processes))
+	case tcell.KeyEnd:
+		state.moveFocus(len(state.processes))
+	case tcell.KeyEscape:
+		w.quit()
+		return true
+	case tcell.KeyRune:
+		switch r := ev.Rune(); r {
+		case 'q':
+			w.quit()
+			return true
+		case 'g':
+			state.moveFocus(-len(state.processes))
+		case 'G':
+			state.moveFocus(len(state.processes))
+		case 'r':
+			w.refresh(results_ch)
+		case '1', '2', '3', '4', '5', '6':
+			state.sortBy(processColumn(r - '1'))
+		case 'k':
+			w.openSignalMenu(state)
+		}
+	}
+	return false
+}
+
+func (w *TopWindow) openSignalMenu(state *topState) {
+	process, ok := state.focusedProcess()
+	if !ok {
+		return
+	}
+	if config.IsReadOnly() {
+		state.status = config.ErrReadOnly.Error()
+		return
+	}
+	items := make([]window.MenuItem, len(processSignals))
+	for i, signal := range processSignals {
+		items[i] = window.MenuItem{
+			Label:   signal,
+			Message: signalRequest{process: process, signal: signal},
+		}
+	}
+	// the menu opens next to the focused row
+	window.GetScreen().PostEvent(window.NewChangeToMenuEvent(fmt.Sprintf("Signal %d", process.PID), items,
+		2, state.focused-state.top_row+2, window.Top))
+}
+
+func (w *TopWindow) quit() {
+	w.window_cancel()
+	window.GetScreen().PostEvent(window.NewReturnUpperViewEvent())
+}
+
+func (w *TopWindow) pageHeight() int {
+	dimensions := w.dimensions_generator()
+	return window.Height(&dimensions) - 3
+}
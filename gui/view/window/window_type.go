@@ -12,5 +12,15 @@ const (
 	Edittor
 	Subshell
 	Error
+	Confirm
+	Menu
+	Prompt
+	Form
+	CastPlayer
+	CommandOutput
+	FileBrowser
+	Pager
+	Diff
+	Top
 	Other
 )
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"dc-top/config"
 	"dc-top/docker"
 	"dc-top/docker/compose"
 	"dc-top/gui"
@@ -19,8 +20,22 @@ func main() {
 	defer logger.Cleanup()
 
 	dc_file_path := flag.String("f", "", "path of docker-compose.yaml file")
+	read_only := flag.Bool("read-only", false, "disable all actions that modify containers or docker-compose")
+	config_path := flag.String("config", "", fmt.Sprintf("path of dc-top config file (default %s)", config.DefaultPath()))
 	flag.Parse()
 
+	if *config_path != "" {
+		err = config.Init(*config_path, true)
+	} else {
+		err = config.Init(config.DefaultPath(), false)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	config.SetReadOnly(*read_only)
+
 	if *dc_file_path != "" {
 		if err = compose.Init(context.Background(), *dc_file_path); err != nil {
 			fmt.Println(err)
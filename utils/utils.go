@@ -5,7 +5,9 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"strings"
 	"time"
+	"unicode"
 	"unsafe"
 )
 
@@ -85,3 +87,62 @@ func RandSeq(n int) string {
 	}
 	return string(b)
 }
+
+// SplitArgs splits a command line by whitespace, keeping single or double quoted parts together
+func SplitArgs(command_line string) []string {
+	args := make([]string, 0)
+	var current strings.Builder
+	var quote rune
+	in_arg := false
+	for _, r := range command_line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			in_arg = true
+		case unicode.IsSpace(r):
+			if in_arg {
+				args = append(args, current.String())
+				current.Reset()
+				in_arg = false
+			}
+		default:
+			current.WriteRune(r)
+			in_arg = true
+		}
+	}
+	if in_arg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// QuoteArgs joins args into a command line that SplitArgs (or a shell) splits back into the same args
+func QuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"$`\\|&;<>(){}*?!#~") {
+			quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'"'"'`) + "'"
+		} else {
+			quoted[i] = arg
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+func Min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func Max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}